@@ -0,0 +1,401 @@
+// Package browse implements an interactive terminal interface for browsing a
+// journal.
+package browse
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+
+	"github.com/taylorskalyo/markdown-journal/journal"
+)
+
+// pane identifies a list in the browser.
+type pane int
+
+const (
+	timelinePane pane = iota
+	labelsPane
+)
+
+// Options stores options for a Browser.
+type Options struct {
+	// Editor is the command used to open entries. It may include arguments,
+	// e.g. "code --wait". The entry's file is appended to it.
+	Editor string
+
+	// Reload, if not nil, is called to reload the journal after an entry is
+	// edited.
+	Reload func() (journal.Journal, error)
+}
+
+// Option applies an option to an Options struct.
+type Option func(*Options)
+
+// Browser is an interactive, full-screen view of a journal. It shows the
+// timeline and labels of the journal side by side with a preview of the
+// selected entry or label.
+type Browser struct {
+	journal journal.Journal
+	opts    Options
+
+	// Entries and labels that match the filter, and the selected label, if
+	// any, that entries must have.
+	entries []journal.Entry
+	labels  []journal.Label
+	label   string
+
+	filter    string
+	filtering bool
+
+	focus  pane
+	cursor [2]int
+	offset [2]int
+	scroll int
+
+	// Height of the lists as last drawn, used to page through them.
+	listHeight int
+
+	// Preview lines of each file, read as needed.
+	sources map[string][]string
+	status  string
+}
+
+// NewBrowser returns a new Browser for the given journal.
+func NewBrowser(j journal.Journal, setters ...Option) *Browser {
+	opts := Options{Editor: os.Getenv("EDITOR")}
+	if opts.Editor == "" {
+		opts.Editor = "vi"
+	}
+	for _, setter := range setters {
+		setter(&opts)
+	}
+
+	b := &Browser{journal: j, opts: opts, sources: map[string][]string{}, listHeight: 1}
+	b.refresh()
+
+	return b
+}
+
+// Editor sets the Editor Option value.
+func Editor(command string) Option {
+	return func(opts *Options) {
+		opts.Editor = command
+	}
+}
+
+// Reload sets the Reload Option value.
+func Reload(f func() (journal.Journal, error)) Option {
+	return func(opts *Options) {
+		opts.Reload = f
+	}
+}
+
+// Run shows the browser in the controlling terminal until the user quits or
+// the context is done. The browser is redrawn when the terminal is resized.
+func (b *Browser) Run(ctx context.Context) (err error) {
+	t, err := openTerminal()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := t.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	resized := make(chan os.Signal, 1)
+	notifyResize(resized)
+	defer signal.Stop(resized)
+
+	width, height, err := t.size()
+	if err != nil {
+		return err
+	}
+
+	// Keys are read in the background, but only while waiting for a key, so
+	// that input is not taken from the editor.
+	read := make(chan struct{})
+	defer close(read)
+	keys := make(chan []byte, 1)
+	errs := make(chan error, 1)
+	go func() {
+		buf := make([]byte, 64)
+		for range read {
+			n, err := t.Read(buf)
+			if err != nil {
+				errs <- err
+				return
+			}
+			keys <- append([]byte(nil), buf[:n]...)
+		}
+	}()
+
+	reading := false
+	for {
+		if _, err = t.WriteString(cursorHome + strings.Join(b.frame(width, height), "\r\n")); err != nil {
+			return err
+		}
+		if !reading {
+			read <- struct{}{}
+			reading = true
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-resized:
+			if width, height, err = t.size(); err != nil {
+				return err
+			}
+			t.WriteString(clearScreen)
+		case err = <-errs:
+			return err
+		case input := <-keys:
+			reading = false
+			for _, key := range decodeKeys(input) {
+				quit, file := b.update(key)
+				if quit {
+					return nil
+				}
+				if file != "" {
+					if err = b.edit(t, file); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+}
+
+// edit opens a file in the editor, then reloads the journal.
+func (b *Browser) edit(t *terminal, file string) error {
+	if err := t.restore(); err != nil {
+		return err
+	}
+
+	args := append(strings.Fields(b.opts.Editor), file)
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = t.File, t.File, t.File
+	if err := cmd.Run(); err != nil {
+		b.status = err.Error()
+	}
+
+	if err := t.raw(); err != nil {
+		return err
+	}
+	t.WriteString(clearScreen)
+
+	delete(b.sources, file)
+	if b.opts.Reload != nil {
+		j, err := b.opts.Reload()
+		if err != nil {
+			b.status = err.Error()
+			return nil
+		}
+		b.journal = j
+		b.refresh()
+	}
+
+	return nil
+}
+
+// update handles a key. It returns true if the browser should quit, or the file
+// of an entry to open in the editor.
+func (b *Browser) update(key string) (quit bool, edit string) {
+	b.status = ""
+
+	if b.filtering {
+		switch key {
+		case keyEnter:
+			b.filtering = false
+		case keyEscape:
+			b.filtering = false
+			b.filter = ""
+		case keyBackspace:
+			if r := []rune(b.filter); len(r) > 0 {
+				b.filter = string(r[:len(r)-1])
+			}
+		case keyInterrupt:
+			return true, ""
+		default:
+			if len([]rune(key)) != 1 {
+				return false, ""
+			}
+			b.filter += key
+		}
+		b.refresh()
+		return false, ""
+	}
+
+	switch key {
+	case "q", keyInterrupt:
+		return true, ""
+	case keyTab, keyLeft, keyRight, "h", "l":
+		b.focus = 1 - b.focus
+		b.scroll = 0
+	case keyDown, "j":
+		b.move(1)
+	case keyUp, "k":
+		b.move(-1)
+	case keyPageDown:
+		b.move(b.listHeight)
+	case keyPageUp:
+		b.move(-b.listHeight)
+	case keyHome, "g":
+		b.move(-b.length())
+	case keyEnd, "G":
+		b.move(b.length())
+	case "J":
+		b.scroll++
+	case "K":
+		if b.scroll > 0 {
+			b.scroll--
+		}
+	case "/":
+		b.filtering = true
+	case keyEscape:
+		b.filter, b.label = "", ""
+		b.refresh()
+	case keyEnter:
+		if b.focus == timelinePane {
+			return false, b.selectedFile()
+		}
+		if l, ok := b.selectedLabel(); ok {
+			if b.label == l.Name {
+				b.label = ""
+			} else {
+				b.label = l.Name
+			}
+			b.focus = timelinePane
+			b.cursor[timelinePane], b.scroll = 0, 0
+			b.refresh()
+		}
+	case "e":
+		return false, b.selectedFile()
+	}
+
+	return false, ""
+}
+
+// move moves the cursor of the focused list by n items.
+func (b *Browser) move(n int) {
+	c := b.cursor[b.focus] + n
+	if c >= b.length() {
+		c = b.length() - 1
+	}
+	if c < 0 {
+		c = 0
+	}
+	if c != b.cursor[b.focus] {
+		b.scroll = 0
+	}
+	b.cursor[b.focus] = c
+}
+
+// length returns the number of items in the focused list.
+func (b *Browser) length() int {
+	if b.focus == labelsPane {
+		return len(b.labels)
+	}
+
+	return len(b.entries)
+}
+
+// refresh updates the lists to match the filter and selected label, keeping the
+// cursors within them.
+func (b *Browser) refresh() {
+	b.entries = nil
+	for _, e := range b.journal.Entries {
+		if b.label != "" && !e.LabelSet()[b.label] {
+			continue
+		}
+		text := strings.Join(append([]string{e.Title(), e.File}, e.Labels()...), " ")
+		if fuzzyMatch(text, b.filter) {
+			b.entries = append(b.entries, e)
+		}
+	}
+
+	b.labels = nil
+	for _, l := range b.journal.Labels {
+		if fuzzyMatch(l.Name, b.filter) {
+			b.labels = append(b.labels, l)
+		}
+	}
+
+	lengths := [2]int{len(b.entries), len(b.labels)}
+	for p, n := range lengths {
+		if b.cursor[p] >= n {
+			b.cursor[p] = n - 1
+		}
+		if b.cursor[p] < 0 {
+			b.cursor[p] = 0
+		}
+	}
+}
+
+func (b *Browser) selectedFile() string {
+	if c := b.cursor[timelinePane]; c < len(b.entries) {
+		return b.entries[c].File
+	}
+
+	return ""
+}
+
+func (b *Browser) selectedLabel() (journal.Label, bool) {
+	if c := b.cursor[labelsPane]; c < len(b.labels) {
+		return b.labels[c], true
+	}
+
+	return journal.Label{}, false
+}
+
+// preview returns the markdown lines to preview: the selected entry's contents,
+// or the occurrences of the selected label.
+func (b *Browser) preview() []string {
+	if b.focus == labelsPane {
+		l, ok := b.selectedLabel()
+		if !ok {
+			return nil
+		}
+		var buf bytes.Buffer
+		j := journal.Journal{Entries: b.journal.Entries, Labels: []journal.Label{l}}
+		j.WriteLabels(&buf, journal.ExcerptLength(80))
+		return strings.Split(strings.TrimSpace(buf.String()), "\n")
+	}
+
+	file := b.selectedFile()
+	if file == "" {
+		return nil
+	}
+	lines, ok := b.sources[file]
+	if !ok {
+		source, err := ioutil.ReadFile(file)
+		if err != nil {
+			return []string{err.Error()}
+		}
+		lines = strings.Split(strings.ReplaceAll(string(source), "\r\n", "\n"), "\n")
+		b.sources[file] = lines
+	}
+
+	return lines
+}
+
+// fuzzyMatch reports whether the characters of pattern appear in text in
+// order, ignoring case.
+func fuzzyMatch(text, pattern string) bool {
+	text = strings.ToLower(text)
+	for _, r := range strings.ToLower(pattern) {
+		i := strings.IndexRune(text, r)
+		if i < 0 {
+			return false
+		}
+		text = text[i+len(string(r)):]
+	}
+
+	return true
+}
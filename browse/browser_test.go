@@ -0,0 +1,127 @@
+package browse
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/taylorskalyo/markdown-journal/journal"
+)
+
+var reEscape = regexp.MustCompile("\x1b\\[[0-9;?]*[a-zA-Z]")
+
+func testBrowser(t *testing.T) *Browser {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"2006-01-02.md": "# Monday :work:\n\nFirst day.\n",
+		"2006-01-03.md": "# Tuesday :work: :food:\n\nLunch :food:\n",
+		"2006-01-04.md": "# Wednesday\n\n```\n:code:\n```\n",
+	}
+	var paths []string
+	for name, source := range files {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, []byte(source), 0644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+
+	entries, err := journal.ParseEntries(paths, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return NewBrowser(journal.FromEntries(entries), Editor("true"))
+}
+
+func titles(b *Browser) (titles []string) {
+	for _, e := range b.entries {
+		titles = append(titles, e.Title())
+	}
+
+	return titles
+}
+
+func TestBrowserUpdate(t *testing.T) {
+	b := testBrowser(t)
+
+	if actual := strings.Join(titles(b), ","); actual != "Wednesday,Tuesday work food,Monday work" {
+		t.Fatalf("unexpected timeline %q", actual)
+	}
+
+	b.update("j")
+	if _, file := b.update(keyEnter); filepath.Base(file) != "2006-01-03.md" {
+		t.Errorf("expected enter to open the selected entry, actual %q", file)
+	}
+
+	b.update(keyTab)
+	if l, _ := b.selectedLabel(); l.Name != "food" {
+		t.Errorf("expected food to be selected, actual %q", l.Name)
+	}
+	b.update(keyEnter)
+	if b.focus != timelinePane || b.label != "food" {
+		t.Errorf("expected selecting a label to focus the timeline")
+	}
+	if actual := strings.Join(titles(b), ","); actual != "Tuesday work food" {
+		t.Errorf("expected entries labeled food, actual %q", actual)
+	}
+
+	b.update(keyEscape)
+	for _, key := range []string{"/", "m", "o", "n", "d", "a", "y", keyEnter} {
+		b.update(key)
+	}
+	if actual := strings.Join(titles(b), ","); actual != "Monday work" {
+		t.Errorf("expected filtered entries, actual %q", actual)
+	}
+	if len(b.labels) != 0 {
+		t.Errorf("expected no matching labels, actual %d", len(b.labels))
+	}
+
+	if quit, _ := b.update("q"); !quit {
+		t.Error("expected q to quit")
+	}
+}
+
+func TestBrowserFrame(t *testing.T) {
+	b := testBrowser(t)
+
+	lines := b.frame(80, 12)
+	if len(lines) != 12 {
+		t.Fatalf("expected 12 lines, actual %d", len(lines))
+	}
+
+	text := reEscape.ReplaceAllString(strings.Join(lines, "\n"), "")
+	for _, s := range []string{"Timeline (3)", "Labels (2)", "2006-01-04  Wednesday", "work (2)", "# Wednesday"} {
+		if !strings.Contains(text, s) {
+			t.Errorf("expected frame to contain %q:\n%s", s, text)
+		}
+	}
+	for i, line := range strings.Split(text, "\n") {
+		if n := len([]rune(line)); n != 80 {
+			t.Errorf("line %d: expected width 80, actual %d", i, n)
+		}
+	}
+}
+
+func TestFuzzyMatch(t *testing.T) {
+	cases := []struct {
+		text, pattern string
+		expected      bool
+	}{
+		{"Monday work", "", true},
+		{"Monday work", "mwk", true},
+		{"Monday work", "WORK", true},
+		{"Monday work", "km", false},
+	}
+
+	for _, tc := range cases {
+		if actual := fuzzyMatch(tc.text, tc.pattern); actual != tc.expected {
+			t.Errorf("%q %q: expected %t, actual %t", tc.text, tc.pattern, tc.expected, actual)
+		}
+	}
+}
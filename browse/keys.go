@@ -0,0 +1,91 @@
+package browse
+
+import "unicode/utf8"
+
+// Names of keys that do not produce a character.
+const (
+	keyUp        = "up"
+	keyDown      = "down"
+	keyLeft      = "left"
+	keyRight     = "right"
+	keyPageUp    = "pgup"
+	keyPageDown  = "pgdown"
+	keyHome      = "home"
+	keyEnd       = "end"
+	keyEnter     = "enter"
+	keyEscape    = "esc"
+	keyTab       = "tab"
+	keyBackspace = "backspace"
+	keyInterrupt = "ctrl-c"
+)
+
+// escapes maps the escape sequences sent by terminals to key names.
+var escapes = map[string]string{
+	"\x1b[A":  keyUp,
+	"\x1b[B":  keyDown,
+	"\x1b[C":  keyRight,
+	"\x1b[D":  keyLeft,
+	"\x1bOA":  keyUp,
+	"\x1bOB":  keyDown,
+	"\x1bOC":  keyRight,
+	"\x1bOD":  keyLeft,
+	"\x1b[5~": keyPageUp,
+	"\x1b[6~": keyPageDown,
+	"\x1b[H":  keyHome,
+	"\x1b[F":  keyEnd,
+	"\x1b[1~": keyHome,
+	"\x1b[4~": keyEnd,
+}
+
+// decodeKeys splits input read from a terminal in raw mode into keys. Keys that
+// produce a character are returned as that character; others are returned by
+// name. Unknown escape sequences are ignored.
+func decodeKeys(b []byte) (keys []string) {
+	for len(b) > 0 {
+		switch b[0] {
+		case '\r', '\n':
+			keys = append(keys, keyEnter)
+		case '\t':
+			keys = append(keys, keyTab)
+		case 0x7f, 0x08:
+			keys = append(keys, keyBackspace)
+		case 0x03:
+			keys = append(keys, keyInterrupt)
+		case 0x1b:
+			if len(b) == 1 {
+				keys = append(keys, keyEscape)
+				break
+			}
+
+			// Escape sequences end with a letter or "~".
+			n := 2
+			for n < len(b) && n < 8 && !isFinal(b[n-1], n) {
+				n++
+			}
+			if key, ok := escapes[string(b[:n])]; ok {
+				keys = append(keys, key)
+			} else if b[1] != '[' && b[1] != 'O' {
+				// A lone escape followed by other input.
+				keys = append(keys, keyEscape)
+				n = 1
+			}
+			b = b[n:]
+			continue
+		default:
+			r, size := utf8.DecodeRune(b)
+			if r >= ' ' {
+				keys = append(keys, string(r))
+			}
+			b = b[size:]
+			continue
+		}
+		b = b[1:]
+	}
+
+	return keys
+}
+
+// isFinal reports whether c ends an escape sequence of length n.
+func isFinal(c byte, n int) bool {
+	return n > 2 && (c == '~' || c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z')
+}
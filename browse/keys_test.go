@@ -0,0 +1,30 @@
+package browse
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecodeKeys(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected []string
+	}{
+		{"jk", []string{"j", "k"}},
+		{"\r", []string{keyEnter}},
+		{"\t\x7f\x03", []string{keyTab, keyBackspace, keyInterrupt}},
+		{"\x1b", []string{keyEscape}},
+		{"\x1b[A\x1b[B", []string{keyUp, keyDown}},
+		{"\x1bOC", []string{keyRight}},
+		{"\x1b[5~\x1b[6~", []string{keyPageUp, keyPageDown}},
+		{"\x1bq", []string{keyEscape, "q"}},
+		{"\x1b[Z", nil},
+		{"é", []string{"é"}},
+	}
+
+	for _, tc := range cases {
+		if actual := decodeKeys([]byte(tc.input)); !reflect.DeepEqual(actual, tc.expected) {
+			t.Errorf("%q: expected %q, actual %q", tc.input, tc.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package browse
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// reLabel matches labels in a line of markdown, along with the preceding
+// character, if any.
+var reLabel = regexp.MustCompile(`(^|[^\pL\pN:/]):[\pL\pN\pM_-]+:`)
+
+// frame returns the lines of the screen: the timeline above the labels on the
+// left, the preview on the right, and a status line at the bottom.
+func (b *Browser) frame(width, height int) []string {
+	if width < 20 || height < 5 {
+		return []string{fit("terminal too small", width)}
+	}
+
+	left := width / 3
+	if left < 24 {
+		left = 24
+	}
+	if left > width/2 {
+		left = width / 2
+	}
+	right := width - left - 1
+
+	body := height - 1
+	timelineHeight := body * 3 / 5
+	labelsHeight := body - timelineHeight
+	b.listHeight = timelineHeight - 1
+
+	var timeline []string
+	for _, e := range b.entries {
+		title := e.Title()
+		if title == "" {
+			title = e.File
+		}
+		timeline = append(timeline, e.Time.Format("2006-01-02")+"  "+title)
+	}
+	timelineTitle := fmt.Sprintf("Timeline (%d)", len(b.entries))
+	if b.label != "" {
+		timelineTitle = fmt.Sprintf("Timeline :%s: (%d)", b.label, len(b.entries))
+	}
+
+	var labels []string
+	for _, l := range b.labels {
+		labels = append(labels, fmt.Sprintf("%s (%d)", l.Name, len(l.Occurrences)))
+	}
+	labelsTitle := fmt.Sprintf("Labels (%d)", len(b.labels))
+
+	lines := b.list(timelinePane, timelineTitle, timeline, left, timelineHeight)
+	lines = append(lines, b.list(labelsPane, labelsTitle, labels, left, labelsHeight)...)
+
+	preview := b.renderPreview(right, body)
+	for i := range lines {
+		lines[i] += dim + "│" + reset + preview[i]
+	}
+
+	return append(lines, b.statusLine(width))
+}
+
+// list renders a pane's title and the visible items of its list, scrolled to
+// keep the cursor in view.
+func (b *Browser) list(p pane, title string, items []string, width, height int) []string {
+	style := bold
+	if b.focus == p {
+		style = bold + reverse
+	}
+	lines := []string{style + fit(" "+title, width) + reset}
+
+	rows := height - 1
+	cursor := b.cursor[p]
+	if cursor < b.offset[p] {
+		b.offset[p] = cursor
+	}
+	if cursor >= b.offset[p]+rows {
+		b.offset[p] = cursor - rows + 1
+	}
+
+	for i := b.offset[p]; i < b.offset[p]+rows; i++ {
+		if i >= len(items) {
+			lines = append(lines, fit("", width))
+			continue
+		}
+
+		line := fit(" "+items[i], width)
+		switch {
+		case i == cursor && b.focus == p:
+			line = reverse + line + reset
+		case i == cursor:
+			line = bold + line + reset
+		}
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+// renderPreview renders the visible lines of the preview with simple markdown
+// highlighting: headings are bold, code blocks and quotes are dim, and labels
+// are colored.
+func (b *Browser) renderPreview(width, height int) []string {
+	source := b.preview()
+	if last := len(source) - height; b.scroll > last {
+		b.scroll = last
+	}
+	if b.scroll < 0 {
+		b.scroll = 0
+	}
+
+	var lines []string
+	code := false
+	for i, line := range source {
+		fence := strings.HasPrefix(strings.TrimSpace(line), "```")
+		if i < b.scroll {
+			code = code != fence
+			continue
+		}
+		if len(lines) == height {
+			break
+		}
+
+		text := " " + fit(line, width-1)
+		switch {
+		case fence || code:
+			text = dim + text + reset
+		case strings.HasPrefix(line, "#"):
+			text = bold + yellow + text + reset
+		case strings.HasPrefix(strings.TrimSpace(line), ">"):
+			text = dim + text + reset
+		default:
+			text = reLabel.ReplaceAllStringFunc(text, func(m string) string {
+				i := strings.Index(m, ":")
+				return m[:i] + cyan + m[i:] + reset
+			})
+		}
+		code = code != fence
+		lines = append(lines, text)
+	}
+	for len(lines) < height {
+		lines = append(lines, fit("", width))
+	}
+
+	return lines
+}
+
+// statusLine shows the filter being typed, or else the active filters and a
+// summary of the keys.
+func (b *Browser) statusLine(width int) string {
+	if b.filtering {
+		return fit("/"+b.filter+"█", width)
+	}
+
+	text := "tab switch  j/k move  J/K scroll  / filter  enter select  e edit  esc clear  q quit"
+	if b.filter != "" {
+		text = "filter: " + b.filter + "  " + text
+	}
+	if b.status != "" {
+		text = b.status
+	}
+
+	return dim + fit(text, width) + reset
+}
+
+// fit truncates or pads a line to the given width. Tabs are expanded.
+func fit(s string, width int) string {
+	s = strings.ReplaceAll(s, "\t", "    ")
+	if n := utf8.RuneCountInString(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+
+	runes := []rune(s)
+	if len(runes) > width && width > 0 {
+		return string(runes[:width-1]) + "…"
+	}
+
+	return string(runes[:width])
+}
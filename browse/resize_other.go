@@ -0,0 +1,10 @@
+//go:build !aix && !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris
+// +build !aix,!darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!solaris
+
+package browse
+
+import "os"
+
+// notifyResize does nothing on systems without a signal for terminal resizes.
+// The browser keeps the size it had when it started.
+func notifyResize(c chan<- os.Signal) {}
@@ -0,0 +1,15 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package browse
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// notifyResize relays the signal sent when the terminal is resized to c.
+func notifyResize(c chan<- os.Signal) {
+	signal.Notify(c, syscall.SIGWINCH)
+}
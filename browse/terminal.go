@@ -0,0 +1,108 @@
+package browse
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// ANSI escape sequences.
+const (
+	altScreen   = "\x1b[?1049h"
+	mainScreen  = "\x1b[?1049l"
+	hideCursor  = "\x1b[?25l"
+	showCursor  = "\x1b[?25h"
+	cursorHome  = "\x1b[H"
+	clearLine   = "\x1b[K"
+	clearScreen = "\x1b[2J"
+
+	bold    = "\x1b[1m"
+	dim     = "\x1b[2m"
+	reverse = "\x1b[7m"
+	cyan    = "\x1b[36m"
+	yellow  = "\x1b[33m"
+	reset   = "\x1b[0m"
+)
+
+// terminal is the controlling terminal, switched between raw and cooked mode
+// using stty.
+type terminal struct {
+	*os.File
+
+	// state is the terminal's original stty settings.
+	state string
+}
+
+// openTerminal opens the controlling terminal and switches it to raw mode.
+func openTerminal() (*terminal, error) {
+	f, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return nil, fmt.Errorf("browse requires a terminal: %w", err)
+	}
+
+	t := &terminal{File: f}
+	if t.state, err = t.stty("-g"); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if err = t.raw(); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// stty runs stty with the terminal as its input and returns its output.
+func (t *terminal) stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = t.File
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("stty %s: %w", strings.Join(args, " "), err)
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
+
+// raw switches the terminal to raw mode and the alternate screen.
+func (t *terminal) raw() error {
+	if _, err := t.stty("raw", "-echo"); err != nil {
+		return err
+	}
+	_, err := t.WriteString(altScreen + hideCursor)
+
+	return err
+}
+
+// restore switches the terminal back to its original mode and screen.
+func (t *terminal) restore() error {
+	t.WriteString(showCursor + mainScreen)
+	_, err := t.stty(t.state)
+
+	return err
+}
+
+// Close restores the terminal and closes it.
+func (t *terminal) Close() error {
+	err := t.restore()
+	if closeErr := t.File.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+// size returns the number of columns and rows of the terminal.
+func (t *terminal) size() (width, height int, err error) {
+	out, err := t.stty("size")
+	if err != nil {
+		return 0, 0, err
+	}
+	if _, err = fmt.Sscanf(out, "%d %d", &height, &width); err != nil {
+		return 0, 0, fmt.Errorf("stty size: %w", err)
+	}
+
+	return width, height, nil
+}
@@ -0,0 +1,51 @@
+package commands
+
+import (
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/taylorskalyo/markdown-journal/journal"
+)
+
+var backlinksEntry string
+
+func init() {
+	application.AddCommand(backlinksCommand)
+
+	tagfileDesc := `read entry info from specified tags file; "-" reads tags from stdin`
+	backlinksCommand.Flags().StringVarP(&tagfileName, "tagfile", "f", "", tagfileDesc)
+
+	recurseDesc := `recurse into directories`
+	backlinksCommand.Flags().BoolVarP(&recurse, "recurse", "R", false, recurseDesc)
+
+	levelDesc := `base heading level`
+	backlinksCommand.Flags().IntVarP(&level, "level", "H", 1, levelDesc)
+
+	entryDesc := `only display a "Referenced by" section for specified entry file`
+	backlinksCommand.Flags().StringVarP(&backlinksEntry, "entry", "e", "", entryDesc)
+}
+
+var backlinksCommand = &cobra.Command{
+	Use:   "backlinks [paths]",
+	Short: "Display links between entries",
+	Long: `This command displays, for each journal entry, the entries that link to it
+using markdown links or wiki links (e.g. [[2024-03-15]] or [[2024-03-15-retro|retro]]).`,
+	Args: cobra.ArbitraryArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		j, err := newJournal(cmd.Context(), args)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		opts := []journal.WriterOption{journal.HeadingLevel(level)}
+		if backlinksEntry != "" {
+			err = j.WriteReferencedBy(os.Stdout, backlinksEntry, opts...)
+		} else {
+			err = j.WriteBacklinks(os.Stdout, opts...)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
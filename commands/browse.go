@@ -0,0 +1,70 @@
+package commands
+
+import (
+	"errors"
+	"log"
+
+	"github.com/spf13/cobra"
+	"github.com/taylorskalyo/markdown-journal/browse"
+	"github.com/taylorskalyo/markdown-journal/journal"
+)
+
+var editor string
+
+func init() {
+	application.AddCommand(browseCommand)
+
+	recurseDesc := `recurse into directories`
+	browseCommand.Flags().BoolVarP(&recurse, "recurse", "R", false, recurseDesc)
+
+	editorDesc := `command used to open entries; defaults to $EDITOR, or vi`
+	browseCommand.Flags().StringVar(&editor, "editor", "", editorDesc)
+}
+
+var browseCommand = &cobra.Command{
+	Use:   "browse [paths]",
+	Short: "Browse the journal interactively",
+	Long: `This command shows a full-screen view of the journal: the timeline and labels
+on the left, and a preview of the selected entry or label on the right.
+
+Keys:
+  tab, h, l    switch between the timeline and labels
+  j, k         move down and up (also arrow keys, page up/down, g, G)
+  J, K         scroll the preview
+  /            filter entries and labels; enter keeps the filter, esc clears it
+  enter        open the selected entry, or show only entries with the selected label
+  e            open the selected entry in the editor
+  esc          clear the filter and selected label
+  q            quit`,
+	Args: cobra.ArbitraryArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		j, err := newJournal(cmd.Context(), args)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		// Problems found when reloading are shown in the browser rather than
+		// logged over it.
+		reload := func() (journal.Journal, error) {
+			opts, err := loadOptions()
+			if err != nil {
+				return journal.Journal{}, err
+			}
+			j, err := journal.Load(cmd.Context(), args, opts...)
+			var diagnostics journal.Diagnostics
+			if errors.As(err, &diagnostics) {
+				err = nil
+			}
+			return j, err
+		}
+
+		opts := []browse.Option{browse.Reload(reload)}
+		if editor != "" {
+			opts = append(opts, browse.Editor(editor))
+		}
+
+		if err = browse.NewBrowser(j, opts...).Run(cmd.Context()); err != nil {
+			log.Fatal(err)
+		}
+	},
+}
@@ -0,0 +1,127 @@
+package commands
+
+import (
+	"errors"
+	"log"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/taylorskalyo/markdown-journal/journal"
+)
+
+var (
+	calendarMonth  string
+	calendarYear   string
+	calendarFormat string
+)
+
+func init() {
+	application.AddCommand(calendarCommand)
+
+	tagfileDesc := `read entry info from specified tags file; "-" reads tags from stdin`
+	calendarCommand.Flags().StringVarP(&tagfileName, "tagfile", "f", "", tagfileDesc)
+
+	recurseDesc := `recurse into directories`
+	calendarCommand.Flags().BoolVarP(&recurse, "recurse", "R", false, recurseDesc)
+
+	sinceDesc := `only include entries on or after specified date (YYYY-MM-DD)`
+	calendarCommand.Flags().StringVar(&since, "since", "", sinceDesc)
+
+	untilDesc := `only include entries on or before specified date (YYYY-MM-DD)`
+	calendarCommand.Flags().StringVar(&until, "until", "", untilDesc)
+
+	levelDesc := `base heading level`
+	calendarCommand.Flags().IntVarP(&level, "level", "H", 1, levelDesc)
+
+	monthDesc := `display specified month (YYYY-MM); defaults to the current month`
+	calendarCommand.Flags().StringVar(&calendarMonth, "month", "", monthDesc)
+
+	yearDesc := `display every month of specified year (YYYY)`
+	calendarCommand.Flags().StringVar(&calendarYear, "year", "", yearDesc)
+
+	formatDesc := `output format: markdown or text`
+	calendarCommand.Flags().StringVar(&calendarFormat, "format", string(journal.CalendarMarkdown), formatDesc)
+
+	localeDesc := `language used for month and weekday names`
+	calendarCommand.Flags().StringVar(&localeName, "locale", journal.DefaultLocale, localeDesc)
+
+	weekStartDesc := `first day of the week`
+	calendarCommand.Flags().StringVar(&weekStart, "week-start", "monday", weekStartDesc)
+}
+
+var calendarCommand = &cobra.Command{
+	Use:   "calendar [paths]",
+	Short: "Display a calendar view",
+	Long: `This command displays a calendar grid for a month or a year, marking days
+that have journal entries and linking to them. If neither --month nor --year is
+given, the months spanned by --since and --until are displayed, or else the
+current month.`,
+	Args: cobra.ArbitraryArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		from, to, err := calendarRange()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		weekday, err := journal.ParseWeekday(weekStart)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		j, err := newJournal(cmd.Context(), args)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		opts, err := dateOptions()
+		if err != nil {
+			log.Fatal(err)
+		}
+		opts = append(opts,
+			journal.HeadingLevel(level),
+			journal.WeekStart(weekday),
+			journal.Calendar(journal.CalendarFormat(calendarFormat)),
+		)
+
+		if err = j.WriteCalendar(os.Stdout, from, to, opts...); err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+// calendarRange returns the first and last days to display.
+func calendarRange() (from, to time.Time, err error) {
+	if calendarYear != "" && calendarMonth != "" {
+		return from, to, errors.New("--year cannot be used with --month")
+	}
+
+	switch {
+	case calendarYear != "":
+		from, err = time.Parse("2006", calendarYear)
+		return from, from.AddDate(1, 0, -1), err
+	case calendarMonth != "":
+		from, err = time.Parse("2006-01", calendarMonth)
+		return from, from.AddDate(0, 1, -1), err
+	}
+
+	if from, err = parseDate(since); err != nil {
+		return from, to, err
+	}
+	if to, err = parseDate(until); err != nil {
+		return from, to, err
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	switch {
+	case from.IsZero() && to.IsZero():
+		from, to = today, today
+	case from.IsZero():
+		from = to
+	case to.IsZero():
+		to = today
+	}
+
+	return from, to, nil
+}
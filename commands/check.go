@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/taylorskalyo/markdown-journal/journal"
+)
+
+func init() {
+	application.AddCommand(checkCommand)
+
+	tagfileDesc := `read entry info from specified tags file; "-" reads tags from stdin`
+	checkCommand.Flags().StringVarP(&tagfileName, "tagfile", "f", "", tagfileDesc)
+
+	recurseDesc := `recurse into directories`
+	checkCommand.Flags().BoolVarP(&recurse, "recurse", "R", false, recurseDesc)
+
+	normalizeDesc := `treat labels differing in case, unicode form, or separators as the same label (case, unicode, separators, all, or none)`
+	checkCommand.Flags().StringSliceVar(&normalize, "normalize", nil, normalizeDesc)
+
+	vocabularyDesc := `also report labels that are deprecated or not in the specified vocabulary file`
+	checkCommand.Flags().StringVar(&vocabularyFile, "vocabulary", "", vocabularyDesc)
+}
+
+var checkCommand = &cobra.Command{
+	Use:   "check [paths]",
+	Short: "Check the journal for problems",
+	Long: `This command reports likely mistakes in the journal: links and wiki links to
+missing files, labels used only once, labels that differ only in case or in the
+use of dashes and underscores, files named like entries that do not have a valid
+date, duplicate titles, and entries without headings. If a label vocabulary is
+given, labels that are deprecated or not in the vocabulary are reported too.
+
+Problems are written in "file:line: message" format. The exit status is 1 if
+any problems are found.`,
+	Args: cobra.ArbitraryArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		opts, err := loadOptions()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		// Files that are skipped while loading the journal, such as those with
+		// invalid dates, are reported as problems rather than warnings.
+		var diagnostics journal.Diagnostics
+		j, err := journal.Load(cmd.Context(), args, opts...)
+		if err != nil && !errors.As(err, &diagnostics) {
+			log.Fatal(err)
+		}
+
+		problems := j.Check()
+		for _, d := range diagnostics {
+			line := d.Line
+			if line == 0 {
+				line = 1
+			}
+			problems = append(problems, journal.Problem{File: d.File, Line: line, Message: d.Message})
+		}
+		if vocabularyFile != "" {
+			vocabulary, err := readVocabulary()
+			if err != nil {
+				log.Fatal(err)
+			}
+			problems = append(problems, j.CheckVocabulary(vocabulary)...)
+		}
+		journal.SortProblems(problems)
+		for _, p := range problems {
+			fmt.Println(p)
+		}
+		if len(problems) > 0 {
+			os.Exit(1)
+		}
+	},
+}
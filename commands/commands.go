@@ -1,8 +1,14 @@
 package commands
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
+	"os/signal"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/taylorskalyo/markdown-journal/ctags"
@@ -10,11 +16,59 @@ import (
 )
 
 var (
-	tagfileName string
-	recurse     bool
-	level       int
+	tagfileName  string
+	recurse      bool
+	level        int
+	templateFile string
+	localeName   string
+	yearLayout   string
+	monthLayout  string
+	dayLayout    string
+	groupBy      string
+	weekStart    string
+	order        string
+	limit        int
+	offset       int
+	splitDir     string
+	since        string
+	until        string
+	excerpt      int
+
+	vocabularyFile string
+	normalize      []string
+	labelNotesDir  string
+	jobs           int
+	strict         bool
 )
 
+// Exit statuses.
+const (
+	// exitFatal indicates that the command failed.
+	exitFatal = 1
+
+	// exitPartial indicates that the command succeeded, but skipped files or
+	// tags that could not be loaded.
+	exitPartial = 2
+)
+
+// partial is set when diagnostics are reported as warnings.
+var partial bool
+
+const (
+	dateFormat = "2006-01-02"
+
+	// Excerpt length used when --excerpt is given without a value.
+	defaultExcerptLength = "200"
+)
+
+func init() {
+	jobsDesc := `parse up to N files concurrently; 0 uses GOMAXPROCS`
+	application.PersistentFlags().IntVarP(&jobs, "jobs", "j", 0, jobsDesc)
+
+	strictDesc := `fail instead of skipping files or tags that cannot be loaded`
+	application.PersistentFlags().BoolVar(&strict, "strict", false, strictDesc)
+}
+
 var application = &cobra.Command{
 	Use:   "markdown-journal",
 	Short: "markdown-journal helps you manage a markdown journal",
@@ -26,75 +80,167 @@ var application = &cobra.Command{
 
 // Execute root command.
 func Execute() {
-	if err := application.Execute(); err != nil {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := application.ExecuteContext(ctx); err != nil {
 		fmt.Println(err)
-		os.Exit(1)
+		os.Exit(exitFatal)
+	}
+	if partial {
+		os.Exit(exitPartial)
 	}
 }
 
-func readCtags(tagfileName string) (tagLines []ctags.TagLine, err error) {
-	var tagfile *os.File
-
-	if tagfileName == "-" {
-		tagfile = os.Stdin
-	} else {
-		tagfile, err = os.Open(tagfileName)
-		if err != nil {
-			return tagLines, err
-		}
+// report writes diagnostics to stderr as "warning: file: message" lines,
+// without the timestamps of the standard logger. If err is not a
+// journal.Diagnostics, it is returned unchanged. If --strict is given,
+// diagnostics are returned as an error instead of being treated as warnings.
+func report(err error) error {
+	var diagnostics journal.Diagnostics
+	if !errors.As(err, &diagnostics) {
+		return err
+	}
+	if len(diagnostics) == 0 {
+		return nil
 	}
 
-	r := ctags.NewReader(tagfile)
-	tagLines = r.ReadAll()
+	for _, d := range diagnostics {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", d)
+	}
+	if strict {
+		return fmt.Errorf("%d file(s) could not be loaded", len(diagnostics))
+	}
+	partial = true
 
-	return tagLines, err
+	return nil
 }
 
+// generateCtags parses the given files. Files that cannot be parsed are
+// reported and skipped.
 func generateCtags(filenames []string) (tagLines []ctags.TagLine, err error) {
-	p := journal.NewFileParser()
-	for _, filename := range filenames {
-		lines, err := p.Parse(filename)
-		if err != nil {
-			return tagLines, err
-		}
-		tagLines = append(tagLines, lines...)
+	tagLines, err = journal.ParseFiles(filenames, jobs)
+
+	return tagLines, report(err)
+}
+
+// newJournal loads the journal in the given paths, or the current directory if
+// none are given, and reports any diagnostics.
+func newJournal(ctx context.Context, paths []string) (journal.Journal, error) {
+	opts, err := loadOptions()
+	if err != nil {
+		return journal.Journal{}, err
 	}
 
-	return tagLines, err
+	j, err := journal.Load(ctx, paths, opts...)
+
+	return j, report(err)
 }
 
-func newJournal(filenames []string) (j journal.Journal, err error) {
-	var journalFiles []string
-	var tagLines []ctags.TagLine
+// findJournalFiles finds journal entry files in the given paths, or the
+// current directory if none are given.
+func findJournalFiles(paths []string) ([]string, error) {
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+
+	files, err := journal.Files(paths, recurse)
+
+	return files, report(err)
+}
 
-	if len(filenames) > 0 {
-		journalFiles, err = journal.Files(filenames, recurse)
-	} else {
-		journalFiles, err = journal.Files([]string{"."}, recurse)
+// loadOptions returns the options used to load the journal.
+func loadOptions() ([]journal.LoadOption, error) {
+	opts := []journal.LoadOption{
+		journal.Recurse(recurse),
+		journal.Jobs(jobs),
+		journal.OnlyLabels(labelFilter...),
 	}
+
+	switch tagfileName {
+	case "":
+	case "-":
+		opts = append(opts, journal.TagReader(os.Stdin))
+	default:
+		opts = append(opts, journal.TagFile(tagfileName))
+	}
+
+	sinceTime, err := parseDate(since)
 	if err != nil {
-		return j, err
+		return opts, err
 	}
+	untilTime, err := parseDate(until)
+	if err != nil {
+		return opts, err
+	}
+	opts = append(opts, journal.DateRange(sinceTime, untilTime))
 
-	if tagfileName == "" {
-		tagLines, err = generateCtags(journalFiles)
-	} else {
-		tagLines, err = readCtags(tagfileName)
+	vocabulary, err := readVocabulary()
+	if err != nil {
+		return opts, err
 	}
+
+	normalization, err := journal.ParseNormalization(normalize)
 	if err != nil {
-		return j, err
+		return opts, err
 	}
 
-	// Some files may not have any labels or headings and therefore no ctags
-	// entries. Ensure every file has at least one ctags entry.
-	fileTagLines := make([]ctags.TagLine, len(journalFiles))
-	for i, file := range journalFiles {
-		fileTagLines[i] = ctags.TagLine{
-			TagFile:   file,
-			TagFields: ctags.TagFields{"line": "0"},
+	var notes map[string]journal.LabelNote
+	if labelNotesDir != "" {
+		notes, err = journal.ReadLabelNotes(labelNotesDir)
+		if err != nil {
+			return opts, err
 		}
 	}
-	tagLines = append(tagLines, fileTagLines...)
 
-	return journal.NewJournal(tagLines), nil
+	opts = append(opts, journal.BuildOptions(
+		journal.LabelVocabulary(vocabulary),
+		journal.LabelNormalization(normalization),
+		journal.LabelNotes(notes)))
+
+	return opts, nil
+}
+
+// readVocabulary reads the label vocabulary file, if one is given.
+func readVocabulary() (journal.Vocabulary, error) {
+	if vocabularyFile == "" {
+		return journal.Vocabulary{}, nil
+	}
+
+	return journal.ReadVocabularyFile(vocabularyFile)
+}
+
+// parseDate parses a YYYY-MM-DD date. An empty string yields the zero time.
+func parseDate(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+
+	return time.Parse(dateFormat, s)
+}
+
+// writeTemplate executes the user-defined template file against the journal
+// and writes the result to a writer.
+func writeTemplate(w io.Writer, j journal.Journal, opts ...journal.WriterOption) error {
+	text, err := ioutil.ReadFile(templateFile)
+	if err != nil {
+		return err
+	}
+
+	if err = j.WriteTemplate(w, string(text), opts...); err != nil {
+		return fmt.Errorf("%s: %w", templateFile, err)
+	}
+
+	return nil
+}
+
+// dateOptions returns writer options for formatting dates in the configured
+// locale.
+func dateOptions() ([]journal.WriterOption, error) {
+	locale, ok := journal.LookupLocale(localeName)
+	if !ok {
+		return nil, fmt.Errorf("unsupported locale: %s", localeName)
+	}
+
+	return []journal.WriterOption{journal.DateLocale(locale)}, nil
 }
@@ -0,0 +1,90 @@
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var configFile string
+
+const defaultConfigFile = ".markdown-journal"
+
+func init() {
+	configDesc := `read default option values from specified file`
+	application.PersistentFlags().StringVar(&configFile, "config", defaultConfigFile, configDesc)
+
+	application.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		return loadConfig(cmd)
+	}
+}
+
+// loadConfig sets the value of each of the command's flags that was not given
+// on the command line from the config file. Each line of the config file has
+// the form "name = value", where name is the long name of a flag. A name may be
+// qualified with a command name (e.g. "timeline.day-format") to apply only to
+// that command; qualified names take precedence. Blank lines and lines
+// beginning with "#" are ignored.
+func loadConfig(cmd *cobra.Command) error {
+	f, err := os.Open(configFile)
+	if os.IsNotExist(err) && !cmd.Flags().Changed("config") {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	type setting struct {
+		name, value string
+		line        int
+	}
+
+	var scoped, global []setting
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		p := strings.SplitN(line, "=", 2)
+		if len(p) != 2 {
+			return fmt.Errorf("%s:%d: expected name = value", configFile, n)
+		}
+		s := setting{
+			name:  strings.TrimSpace(p[0]),
+			value: strings.TrimSpace(p[1]),
+			line:  n,
+		}
+
+		if i := strings.LastIndex(s.name, "."); i >= 0 {
+			if s.name[:i] != cmd.Name() {
+				continue
+			}
+			s.name = s.name[i+1:]
+			scoped = append(scoped, s)
+		} else {
+			global = append(global, s)
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return err
+	}
+
+	for _, s := range append(scoped, global...) {
+		flag := cmd.Flags().Lookup(s.name)
+		if flag == nil || flag.Changed {
+			continue
+		}
+
+		if err = cmd.Flags().Set(s.name, s.value); err != nil {
+			return fmt.Errorf("%s:%d: %w", configFile, s.line, err)
+		}
+	}
+
+	return nil
+}
@@ -3,18 +3,25 @@ package commands
 import (
 	"log"
 	"os"
+	"path/filepath"
 	"sort"
 
 	"github.com/spf13/cobra"
 	"github.com/taylorskalyo/markdown-journal/ctags"
-	"github.com/taylorskalyo/markdown-journal/journal"
 )
 
 var (
 	nosort           bool
 	ctagsTagfileName string
+	allKinds         bool
 )
 
+// extraKinds are the kinds of tags whose names are not identifiers: task text,
+// link destinations, and excerpts named after their entry. They are left out
+// of tags files unless --all-kinds is given, so that they do not crowd out
+// headings and labels in an editor's tag lookup.
+var extraKinds = map[string]bool{"task": true, "link": true, "excerpt": true}
+
 func init() {
 	application.AddCommand(ctagsCommand)
 
@@ -26,49 +33,125 @@ func init() {
 
 	recurseDesc := `recurse into subdirectories`
 	ctagsCommand.Flags().BoolVarP(&recurse, "recurse", "R", false, recurseDesc)
+
+	allKindsDesc := `also tag tasks, links, and excerpts`
+	ctagsCommand.Flags().BoolVar(&allKinds, "all-kinds", false, allKindsDesc)
 }
 
 var ctagsCommand = &cobra.Command{
 	Use:   "ctags [paths]",
 	Short: "Generate ctags",
-	Long:  `This command generates a ctags compatible tags file.`,
-	Args:  cobra.ArbitraryArgs,
-	Run: func(cmd *cobra.Command, args []string) {
-		var journalFiles []string
-		var tagLines []ctags.TagLine
-		var err error
-		var tagfile *os.File
+	Long: `This command generates a ctags compatible tags file.
 
-		if len(args) > 0 {
-			journalFiles, err = journal.Files(args, recurse)
-		} else {
-			journalFiles, err = journal.Files([]string{"."}, recurse)
-		}
+Headings and labels are tagged by default. Use --all-kinds to also tag tasks,
+links, and excerpts, so that views read from the tags file can show them.`,
+	Args: cobra.ArbitraryArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		journalFiles, err := findJournalFiles(args)
 		if err != nil {
 			log.Fatal(err)
 		}
 
-		tagLines, err = generateCtags(journalFiles)
-		if err != nil {
+		if err = writeCtags(ctagsTagfileName, journalFiles); err != nil {
 			log.Fatal(err)
 		}
+	},
+}
+
+// writeCtags generates tags for the given journal files and writes them to the
+// named tags file. A name of "-" writes tags to stdout.
+func writeCtags(tagfileName string, journalFiles []string) error {
+	tagLines, err := generateCtags(journalFiles)
+	if err != nil {
+		return err
+	}
+
+	return saveCtags(tagfileName, filterKinds(tagLines, allKinds))
+}
 
-		if ctagsTagfileName == "-" {
-			tagfile = os.Stdout
-		} else {
-			tagfile, err = os.Create(ctagsTagfileName)
-			if err != nil {
-				log.Fatal(err)
-			}
+// updateCtags regenerates the tags of the given journal files in an existing
+// tags file. The tags of all other files are left as they are. Tasks, links,
+// and excerpts are tagged only if the existing file already tags them.
+func updateCtags(tagfileName string, journalFiles []string) error {
+	f, err := os.Open(tagfileName)
+	if err != nil {
+		return err
+	}
+	tagLines := ctags.NewReader(f).ReadAll()
+	f.Close()
+
+	updated := map[string]bool{}
+	for _, file := range journalFiles {
+		updated[absPath(file)] = true
+	}
+	var kept []ctags.TagLine
+	all := false
+	for _, l := range tagLines {
+		all = all || extraKinds[l.Kind()]
+		if !updated[absPath(l.TagFile)] {
+			kept = append(kept, l)
 		}
+	}
+
+	fresh, err := generateCtags(journalFiles)
+	if err != nil {
+		return err
+	}
 
-		if !nosort {
-			sort.Slice(tagLines, func(i, j int) bool {
-				return tagLines[i].TagName < tagLines[j].TagName
-			})
+	return saveCtags(tagfileName, append(kept, filterKinds(fresh, all)...))
+}
+
+// filterKinds returns the tags that are not of an extra kind, or all tags if
+// all is true.
+func filterKinds(tagLines []ctags.TagLine, all bool) []ctags.TagLine {
+	if all {
+		return tagLines
+	}
+
+	var filtered []ctags.TagLine
+	for _, l := range tagLines {
+		if !extraKinds[l.Kind()] {
+			filtered = append(filtered, l)
 		}
+	}
 
-		w := ctags.NewWriter(tagfile)
-		w.WriteAll(tagLines)
-	},
+	return filtered
+}
+
+// saveCtags writes tags to the named tags file, sorted by tag name unless
+// --no-sort is given. A name of "-" writes tags to stdout.
+func saveCtags(tagfileName string, tagLines []ctags.TagLine) error {
+	var tagfile *os.File
+
+	if tagfileName == "-" {
+		tagfile = os.Stdout
+	} else {
+		var err error
+		tagfile, err = os.Create(tagfileName)
+		if err != nil {
+			return err
+		}
+		defer tagfile.Close()
+	}
+
+	if !nosort {
+		sort.SliceStable(tagLines, func(i, j int) bool {
+			return tagLines[i].TagName < tagLines[j].TagName
+		})
+	}
+
+	w := ctags.NewWriter(tagfile)
+	return w.WriteAll(tagLines)
+}
+
+// absPath returns the absolute form of a path, so that paths written
+// differently can be compared. It returns the cleaned path if the absolute
+// path cannot be determined.
+func absPath(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.Clean(path)
+	}
+
+	return abs
 }
@@ -0,0 +1,51 @@
+package commands
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/spf13/cobra"
+	"github.com/taylorskalyo/markdown-journal/dayone"
+)
+
+var outDir string
+
+func init() {
+	application.AddCommand(importCommand)
+	importCommand.AddCommand(dayOneCommand)
+
+	outDesc := `directory to write entries to`
+	dayOneCommand.Flags().StringVarP(&outDir, "out", "o", ".", outDesc)
+}
+
+var importCommand = &cobra.Command{
+	Use:   "import",
+	Short: "Import entries from other journaling apps",
+	Long:  `This command converts entries exported from other journaling apps into journal entries.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Usage()
+	},
+}
+
+var dayOneCommand = &cobra.Command{
+	Use:   "dayone export.json",
+	Short: "Import entries from a Day One JSON export",
+	Long: `This command converts each entry of an unzipped Day One JSON export into a
+markdown file named after its date and first line of text, skipping photos,
+e.g. 2006-01-02-title.md.
+The entry's creation time and location are kept in front matter, its tags are
+converted to labels, and its photos are copied into an attachments directory.
+
+Existing files are never overwritten; entries that would overwrite a file are
+reported and skipped.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		written, err := dayone.Import(args[0], outDir)
+		for _, file := range written {
+			fmt.Println(file)
+		}
+		if err = report(err); err != nil {
+			log.Fatal(err)
+		}
+	},
+}
@@ -1,13 +1,27 @@
 package commands
 
 import (
+	"fmt"
 	"log"
 	"os"
+	"path/filepath"
 
 	"github.com/spf13/cobra"
 	"github.com/taylorskalyo/markdown-journal/journal"
 )
 
+var (
+	labelStats     bool
+	labelStatsJSON bool
+	cooccurrence   string
+	labelFilter    []string
+	section        int
+	sectionStyle   string
+)
+
+// Number of section lines used when --section is given without a value.
+const defaultSectionLength = "10"
+
 func init() {
 	application.AddCommand(labelsCommand)
 
@@ -17,8 +31,58 @@ func init() {
 	recurseDesc := `recurse into directories`
 	labelsCommand.Flags().BoolVarP(&recurse, "recurse", "R", false, recurseDesc)
 
+	vocabularyDesc := `merge label aliases and deprecated names using the specified vocabulary file`
+	labelsCommand.Flags().StringVar(&vocabularyFile, "vocabulary", "", vocabularyDesc)
+
+	normalizeDesc := `treat labels differing in case, unicode form, or separators as the same label (case, unicode, separators, all, or none)`
+	labelsCommand.Flags().StringSliceVar(&normalize, "normalize", nil, normalizeDesc)
+
+	labelDesc := `only display the specified label; may be repeated`
+	labelsCommand.Flags().StringSliceVarP(&labelFilter, "label", "l", nil, labelDesc)
+
+	notesDirDesc := `read label descriptions from markdown files named after each label in specified directory`
+	labelsCommand.Flags().StringVar(&labelNotesDir, "notes-dir", "", notesDirDesc)
+
+	sinceDesc := `only include entries on or after specified date (YYYY-MM-DD)`
+	labelsCommand.Flags().StringVar(&since, "since", "", sinceDesc)
+
+	untilDesc := `only include entries on or before specified date (YYYY-MM-DD)`
+	labelsCommand.Flags().StringVar(&until, "until", "", untilDesc)
+
 	levelDesc := `base heading level`
 	labelsCommand.Flags().IntVarP(&level, "level", "H", 1, levelDesc)
+
+	localeDesc := `language used for month and weekday names in templates`
+	labelsCommand.Flags().StringVar(&localeName, "locale", journal.DefaultLocale, localeDesc)
+
+	statsDesc := `display label usage statistics, trends, and co-occurrence`
+	labelsCommand.Flags().BoolVar(&labelStats, "stats", false, statsDesc)
+
+	jsonDesc := `write label statistics as JSON`
+	labelsCommand.Flags().BoolVar(&labelStatsJSON, "json", false, jsonDesc)
+
+	byDesc := `aggregate label trends by day, isoweek, month, or year`
+	labelsCommand.Flags().StringVar(&statsBy, "by", string(journal.GroupByMonth), byDesc)
+
+	scopeDesc := `count labels appearing together in the same entry or section`
+	labelsCommand.Flags().StringVar(&cooccurrence, "cooccurrence", string(journal.ScopeEntry), scopeDesc)
+
+	splitDirDesc := `write one file per label and an index.md file to specified directory`
+	labelsCommand.Flags().StringVar(&splitDir, "split-dir", "", splitDirDesc)
+
+	excerptDesc := `include up to N characters of each entry's excerpt`
+	labelsCommand.Flags().IntVar(&excerpt, "excerpt", 0, excerptDesc)
+	labelsCommand.Flags().Lookup("excerpt").NoOptDefVal = defaultExcerptLength
+
+	sectionDesc := `include up to N lines of the section each label occurs in`
+	labelsCommand.Flags().IntVar(&section, "section", 0, sectionDesc)
+	labelsCommand.Flags().Lookup("section").NoOptDefVal = defaultSectionLength
+
+	sectionStyleDesc := `include sections as a blockquote (quote) or indented text (inline)`
+	labelsCommand.Flags().StringVar(&sectionStyle, "section-style", string(journal.SectionQuote), sectionStyleDesc)
+
+	templateDesc := `render output using the specified text/template file`
+	labelsCommand.Flags().StringVarP(&templateFile, "template", "t", "", templateDesc)
 }
 
 var labelsCommand = &cobra.Command{
@@ -27,10 +91,62 @@ var labelsCommand = &cobra.Command{
 	Long:  `This command displays a list of journal entries categorized by label.`,
 	Args:  cobra.ArbitraryArgs,
 	Run: func(cmd *cobra.Command, args []string) {
-		j, err := newJournal(args)
+		j, err := newJournal(cmd.Context(), args)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		opts, err := dateOptions()
+		if err != nil {
+			log.Fatal(err)
+		}
+		opts = append(opts, journal.HeadingLevel(level), journal.ExcerptLength(excerpt),
+			journal.SectionLength(section), journal.SectionStyle(journal.SectionFormat(sectionStyle)))
+
+		switch {
+		case labelStats:
+			err = writeLabelStats(j, opts...)
+		case templateFile != "":
+			err = writeTemplate(os.Stdout, j, opts...)
+		case splitDir != "":
+			err = writeLabelsSplit(j, opts...)
+		default:
+			err = j.WriteLabels(os.Stdout, opts...)
+		}
 		if err != nil {
 			log.Fatal(err)
 		}
-		j.WriteLabels(os.Stdout, journal.HeadingLevel(level))
 	},
 }
+
+func writeLabelStats(j journal.Journal, opts ...journal.WriterOption) error {
+	report, err := j.LabelStats(journal.Grouping(statsBy), journal.Scope(cooccurrence))
+	if err != nil {
+		return err
+	}
+
+	if labelStatsJSON {
+		return report.WriteJSON(os.Stdout)
+	}
+
+	return report.WriteLabelStats(os.Stdout, opts...)
+}
+
+func writeLabelsSplit(j journal.Journal, opts ...journal.WriterOption) error {
+	// Label pages would overwrite the notes they are generated from.
+	if labelNotesDir != "" {
+		split, err := filepath.Abs(splitDir)
+		if err != nil {
+			return err
+		}
+		notes, err := filepath.Abs(labelNotesDir)
+		if err != nil {
+			return err
+		}
+		if split == notes {
+			return fmt.Errorf("split directory must differ from the label notes directory: %s", labelNotesDir)
+		}
+	}
+
+	return j.WriteLabelsSplit(splitDir, opts...)
+}
@@ -0,0 +1,102 @@
+package commands
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/taylorskalyo/markdown-journal/journal"
+)
+
+var highlight bool
+
+func init() {
+	application.AddCommand(listCommand)
+	listCommand.AddCommand(previewCommand)
+
+	tagfileDesc := `read entry info from specified tags file; "-" reads tags from stdin`
+	listCommand.Flags().StringVarP(&tagfileName, "tagfile", "f", "", tagfileDesc)
+
+	recurseDesc := `recurse into directories`
+	listCommand.Flags().BoolVarP(&recurse, "recurse", "R", false, recurseDesc)
+
+	sinceDesc := `only include entries on or after specified date (YYYY-MM-DD)`
+	listCommand.Flags().StringVar(&since, "since", "", sinceDesc)
+
+	untilDesc := `only include entries on or before specified date (YYYY-MM-DD)`
+	listCommand.Flags().StringVar(&until, "until", "", untilDesc)
+
+	orderDesc := `list entries in asc (oldest first) or desc (newest first) order`
+	listCommand.Flags().StringVar(&order, "order", string(journal.Descending), orderDesc)
+
+	highlightDesc := `highlight the line with ANSI escape codes instead of marking it with ">" (default true if stdout is a terminal)`
+	previewCommand.Flags().BoolVar(&highlight, "color", false, highlightDesc)
+}
+
+var listCommand = &cobra.Command{
+	Use:   "list [paths]",
+	Short: "List entries, headings, and labels for fuzzy finders",
+	Long: `This command lists every entry, heading, and label occurrence in the journal,
+one per line, for use with fuzzy finders such as fzf or skim. Lines have four
+tab-separated fields:
+
+  path:line	date	title	labels
+
+For example:
+
+  markdown-journal list -R | fzf --delimiter '\t' --with-nth 2.. \
+    --preview 'markdown-journal list preview --color {1}'`,
+	Args: cobra.ArbitraryArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		j, err := newJournal(cmd.Context(), args)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if order != string(journal.Ascending) && order != string(journal.Descending) {
+			log.Fatalf("unknown order: %s", order)
+		}
+
+		if err = j.WriteList(os.Stdout, journal.EntryOrder(journal.Order(order))); err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var previewCommand = &cobra.Command{
+	Use:   "preview path[:line]",
+	Short: "Preview an entry section",
+	Long: `This command prints the section of an entry that contains the given line,
+starting with its heading, with the line highlighted. Without a line, the whole
+entry is printed.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		file, line := args[0], 0
+		if i := strings.LastIndex(file, ":"); i >= 0 {
+			if n, err := strconv.Atoi(file[i+1:]); err == nil {
+				file, line = file[:i], n
+			}
+		}
+
+		if !cmd.Flags().Changed("color") {
+			highlight = isTerminal(os.Stdout)
+		}
+
+		e, err := journal.NewFileParser().ParseEntry(file)
+		if err != nil {
+			log.Fatalf("%s: %s", file, err)
+		}
+
+		if err = e.WritePreview(os.Stdout, line, journal.HighlightLine(highlight)); err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+// isTerminal reports whether f is a terminal.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,63 @@
+package commands
+
+import (
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/taylorskalyo/markdown-journal/journal"
+	"github.com/taylorskalyo/markdown-journal/lsp"
+)
+
+func init() {
+	application.AddCommand(lspCommand)
+
+	normalizeDesc := `treat labels differing in case, unicode form, or separators as the same label (case, unicode, separators, all, or none)`
+	lspCommand.Flags().StringSliceVar(&normalize, "normalize", nil, normalizeDesc)
+
+	vocabularyDesc := `use canonical labels from the specified vocabulary file and report labels that are deprecated or not in it`
+	lspCommand.Flags().StringVar(&vocabularyFile, "vocabulary", "", vocabularyDesc)
+
+	notesDesc := `read label descriptions from notes in the specified directory`
+	lspCommand.Flags().StringVar(&labelNotesDir, "notes-dir", "", notesDesc)
+}
+
+var lspCommand = &cobra.Command{
+	Use:   "lsp",
+	Short: "Run a language server for the journal",
+	Long: `This command runs a Language Server Protocol server over stdin and stdout, for
+use with editors such as VS Code, Neovim, and Helix. The journal is loaded from
+the editor's workspace root, including subdirectories.
+
+The server completes existing labels after ":", finds the definition of a label
+(its first use) and its references (all of its occurrences), shows label counts
+on hover, lists headings as document symbols and entry titles as workspace
+symbols, and reports the problems found by the check command as diagnostics.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		vocabulary, err := readVocabulary()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		normalization, err := journal.ParseNormalization(normalize)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		var notes map[string]journal.LabelNote
+		if labelNotesDir != "" {
+			if notes, err = journal.ReadLabelNotes(labelNotesDir); err != nil {
+				log.Fatal(err)
+			}
+		}
+
+		server := lsp.NewServer(os.Stdin, os.Stdout,
+			journal.LabelVocabulary(vocabulary),
+			journal.LabelNormalization(normalization),
+			journal.LabelNotes(notes))
+		if err := server.Serve(cmd.Context()); err != nil {
+			log.Fatal(err)
+		}
+	},
+}
@@ -0,0 +1,79 @@
+package commands
+
+import (
+	"log"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/taylorskalyo/markdown-journal/journal"
+)
+
+var (
+	onThisDayDate string
+	windows       []string
+)
+
+func init() {
+	application.AddCommand(onThisDayCommand)
+
+	tagfileDesc := `read entry info from specified tags file; "-" reads tags from stdin`
+	onThisDayCommand.Flags().StringVarP(&tagfileName, "tagfile", "f", "", tagfileDesc)
+
+	recurseDesc := `recurse into directories`
+	onThisDayCommand.Flags().BoolVarP(&recurse, "recurse", "R", false, recurseDesc)
+
+	levelDesc := `base heading level`
+	onThisDayCommand.Flags().IntVarP(&level, "level", "H", 1, levelDesc)
+
+	dateDesc := `look back from specified date (YYYY-MM-DD); defaults to today`
+	onThisDayCommand.Flags().StringVarP(&onThisDayDate, "date", "d", "", dateDesc)
+
+	windowDesc := `also list entries from a span before the date, e.g. 1w, 1m, 6m`
+	onThisDayCommand.Flags().StringSliceVarP(&windows, "window", "w", nil, windowDesc)
+
+	localeDesc := `language used for month and weekday names`
+	onThisDayCommand.Flags().StringVar(&localeName, "locale", journal.DefaultLocale, localeDesc)
+}
+
+var onThisDayCommand = &cobra.Command{
+	Use:   "onthisday [paths]",
+	Short: "Display entries from this day in previous years",
+	Long: `This command lists entries written on the same month and day in previous
+years, along with their labels and first paragraph.`,
+	Args: cobra.ArbitraryArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		date, err := parseDate(onThisDayDate)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if date.IsZero() {
+			now := time.Now()
+			date = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		}
+
+		var spans []journal.Window
+		for _, s := range windows {
+			win, err := journal.ParseWindow(s)
+			if err != nil {
+				log.Fatal(err)
+			}
+			spans = append(spans, win)
+		}
+
+		j, err := newJournal(cmd.Context(), args)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		opts, err := dateOptions()
+		if err != nil {
+			log.Fatal(err)
+		}
+		opts = append(opts, journal.HeadingLevel(level))
+
+		if err = j.WriteOnThisDay(os.Stdout, date, spans, opts...); err != nil {
+			log.Fatal(err)
+		}
+	},
+}
@@ -0,0 +1,122 @@
+package commands
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/taylorskalyo/markdown-journal/journal"
+	"github.com/taylorskalyo/markdown-journal/markdown/extension"
+)
+
+var dryRun bool
+
+func init() {
+	labelsCommand.AddCommand(renameCommand, mergeCommand)
+
+	for _, cmd := range []*cobra.Command{renameCommand, mergeCommand} {
+		tagfileDesc := `update specified tags file afterwards, if it exists`
+		cmd.Flags().StringVarP(&ctagsTagfileName, "tagfile", "f", "tags", tagfileDesc)
+
+		recurseDesc := `recurse into directories`
+		cmd.Flags().BoolVarP(&recurse, "recurse", "R", false, recurseDesc)
+
+		dryRunDesc := `display a unified diff of the changes instead of making them`
+		cmd.Flags().BoolVarP(&dryRun, "dry-run", "n", false, dryRunDesc)
+	}
+}
+
+var renameCommand = &cobra.Command{
+	Use:   "rename old new [paths]",
+	Short: "Rename a label",
+	Long: `This command renames a label everywhere it occurs in the journal. Only actual
+labels are renamed; text in code blocks, URLs, etc. is left untouched.`,
+	Args: cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		relabel(map[string]string{args[0]: args[1]}, args[2:])
+	},
+}
+
+var mergeCommand = &cobra.Command{
+	Use:   "merge label... into target [paths]",
+	Short: "Merge labels into one",
+	Long: `This command renames each of the given labels to the target label everywhere
+they occur in the journal. Only actual labels are renamed; text in code blocks,
+URLs, etc. is left untouched.`,
+	Args: cobra.MinimumNArgs(3),
+	Run: func(cmd *cobra.Command, args []string) {
+		into := -1
+		for i, arg := range args {
+			if arg == "into" {
+				into = i
+				break
+			}
+		}
+		if into < 1 || into == len(args)-1 {
+			log.Fatal(`usage: labels merge label... into target [paths]`)
+		}
+
+		target := args[into+1]
+		renames := map[string]string{}
+		for _, label := range args[:into] {
+			renames[label] = target
+		}
+
+		relabel(renames, args[into+2:])
+	},
+}
+
+// relabel renames labels in the journal files found in paths, then updates the
+// tags of the rewritten files in the tags file.
+func relabel(renames map[string]string, paths []string) {
+	for _, name := range renames {
+		if !extension.IsLabel(name) {
+			log.Fatalf("invalid label: %s", name)
+		}
+	}
+
+	journalFiles, err := findJournalFiles(paths)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	p := journal.NewFileParser()
+	var changed []string
+	for _, file := range journalFiles {
+		r, err := p.RewriteLabels(file, renames)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if r.Count == 0 {
+			continue
+		}
+		changed = append(changed, file)
+
+		if dryRun {
+			fmt.Print(r.Diff())
+			continue
+		}
+
+		info, err := os.Stat(file)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err = ioutil.WriteFile(file, r.New, info.Mode()); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Fprintf(os.Stderr, "%s: renamed %d label(s)\n", file, r.Count)
+	}
+
+	if dryRun || len(changed) == 0 || ctagsTagfileName == "-" {
+		return
+	}
+
+	if _, err := os.Stat(ctagsTagfileName); err != nil {
+		return
+	}
+	if err := updateCtags(ctagsTagfileName, changed); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,145 @@
+package commands
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/taylorskalyo/markdown-journal/journal"
+)
+
+var (
+	statsBy     string
+	heatmap     string
+	heatmapFile string
+)
+
+func init() {
+	application.AddCommand(statsCommand)
+
+	tagfileDesc := `read entry info from specified tags file; "-" reads tags from stdin`
+	statsCommand.Flags().StringVarP(&tagfileName, "tagfile", "f", "", tagfileDesc)
+
+	recurseDesc := `recurse into directories`
+	statsCommand.Flags().BoolVarP(&recurse, "recurse", "R", false, recurseDesc)
+
+	sinceDesc := `only include entries on or after specified date (YYYY-MM-DD)`
+	statsCommand.Flags().StringVar(&since, "since", "", sinceDesc)
+
+	untilDesc := `only include entries on or before specified date (YYYY-MM-DD)`
+	statsCommand.Flags().StringVar(&until, "until", "", untilDesc)
+
+	levelDesc := `base heading level`
+	statsCommand.Flags().IntVarP(&level, "level", "H", 1, levelDesc)
+
+	byDesc := `aggregate activity by day, isoweek, month, or year; "none" lists each entry`
+	statsCommand.Flags().StringVar(&statsBy, "by", string(journal.GroupByMonth), byDesc)
+
+	heatmapDesc := `heatmap format: text, svg, or none`
+	statsCommand.Flags().StringVar(&heatmap, "heatmap", "text", heatmapDesc)
+
+	heatmapFileDesc := `write svg heatmap to specified file; "-" writes to stdout`
+	statsCommand.Flags().StringVar(&heatmapFile, "heatmap-file", "heatmap.svg", heatmapFileDesc)
+
+	localeDesc := `language used for month and weekday names`
+	statsCommand.Flags().StringVar(&localeName, "locale", journal.DefaultLocale, localeDesc)
+
+	weekStartDesc := `first day of the week in the heatmap`
+	statsCommand.Flags().StringVar(&weekStart, "week-start", "monday", weekStartDesc)
+}
+
+var statsCommand = &cobra.Command{
+	Use:   "stats [paths]",
+	Short: "Display writing statistics",
+	Long: `This command displays word counts, entries per period, daily streaks, the
+busiest weekdays, and a heatmap of writing activity. The heatmap covers --since
+through --until, or else the last year.`,
+	Args: cobra.ArbitraryArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		weekday, err := journal.ParseWeekday(weekStart)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		j, err := newJournal(cmd.Context(), args)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		opts, err := dateOptions()
+		if err != nil {
+			log.Fatal(err)
+		}
+		opts = append(opts, journal.HeadingLevel(level), journal.WeekStart(weekday))
+
+		now := time.Now()
+		stats, err := j.Stats(now)
+		if err = report(err); err != nil {
+			log.Fatal(err)
+		}
+
+		if err = stats.WriteStats(os.Stdout, journal.Grouping(statsBy), opts...); err != nil {
+			log.Fatal(err)
+		}
+
+		from, to, err := heatmapRange(now)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		switch heatmap {
+		case "none":
+		case "text":
+			fmt.Printf("\n%s Heatmap\n\n```\n", strings.Repeat("#", level))
+			err = stats.WriteHeatmap(os.Stdout, from, to, opts...)
+			fmt.Println("```")
+		case "svg":
+			err = writeHeatmapSVG(stats, from, to, opts...)
+		default:
+			err = fmt.Errorf("unknown heatmap format: %s", heatmap)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+// heatmapRange returns the days covered by the heatmap.
+func heatmapRange(now time.Time) (from, to time.Time, err error) {
+	if from, err = parseDate(since); err != nil {
+		return from, to, err
+	}
+	if to, err = parseDate(until); err != nil {
+		return from, to, err
+	}
+
+	if to.IsZero() {
+		to = now
+	}
+	if from.IsZero() {
+		from = to.AddDate(-1, 0, 1)
+	}
+
+	return from, to, nil
+}
+
+func writeHeatmapSVG(stats journal.Stats, from, to time.Time, opts ...journal.WriterOption) error {
+	if heatmapFile == "-" {
+		return stats.WriteHeatmapSVG(os.Stdout, from, to, opts...)
+	}
+
+	f, err := os.Create(heatmapFile)
+	if err != nil {
+		return err
+	}
+
+	err = stats.WriteHeatmapSVG(f, from, to, opts...)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
@@ -17,8 +17,51 @@ func init() {
 	recurseDesc := `recurse into directories`
 	timelineCommand.Flags().BoolVarP(&recurse, "recurse", "R", false, recurseDesc)
 
+	sinceDesc := `only include entries on or after specified date (YYYY-MM-DD)`
+	timelineCommand.Flags().StringVar(&since, "since", "", sinceDesc)
+
+	untilDesc := `only include entries on or before specified date (YYYY-MM-DD)`
+	timelineCommand.Flags().StringVar(&until, "until", "", untilDesc)
+
 	levelDesc := `base heading level`
 	timelineCommand.Flags().IntVarP(&level, "level", "H", 1, levelDesc)
+
+	localeDesc := `language used for month and weekday names`
+	timelineCommand.Flags().StringVar(&localeName, "locale", journal.DefaultLocale, localeDesc)
+
+	yearDesc := `Go layout string for year headings`
+	timelineCommand.Flags().StringVar(&yearLayout, "year-format", "2006", yearDesc)
+
+	monthDesc := `Go layout string for month headings`
+	timelineCommand.Flags().StringVar(&monthLayout, "month-format", "January", monthDesc)
+
+	dayDesc := `Go layout string for days`
+	timelineCommand.Flags().StringVar(&dayLayout, "day-format", "02 Mon", dayDesc)
+
+	groupByDesc := `group entries by year, month, isoweek, week, day, or none`
+	timelineCommand.Flags().StringVar(&groupBy, "group-by", string(journal.GroupByMonth), groupByDesc)
+
+	weekStartDesc := `first day of the week when grouping by week`
+	timelineCommand.Flags().StringVar(&weekStart, "week-start", "monday", weekStartDesc)
+
+	orderDesc := `list entries in asc (oldest first) or desc (newest first) order`
+	timelineCommand.Flags().StringVar(&order, "order", string(journal.Descending), orderDesc)
+
+	limitDesc := `maximum number of entries to list; 0 lists all entries`
+	timelineCommand.Flags().IntVar(&limit, "limit", 0, limitDesc)
+
+	offsetDesc := `number of entries to skip`
+	timelineCommand.Flags().IntVar(&offset, "offset", 0, offsetDesc)
+
+	splitDirDesc := `write one timeline file per year and an index.md file to specified directory`
+	timelineCommand.Flags().StringVar(&splitDir, "split-dir", "", splitDirDesc)
+
+	excerptDesc := `include up to N characters of each entry's excerpt`
+	timelineCommand.Flags().IntVar(&excerpt, "excerpt", 0, excerptDesc)
+	timelineCommand.Flags().Lookup("excerpt").NoOptDefVal = defaultExcerptLength
+
+	templateDesc := `render output using the specified text/template file`
+	timelineCommand.Flags().StringVarP(&templateFile, "template", "t", "", templateDesc)
 }
 
 var timelineCommand = &cobra.Command{
@@ -27,10 +70,53 @@ var timelineCommand = &cobra.Command{
 	Long:  `This command displays a timeline view of journal entries.`,
 	Args:  cobra.ArbitraryArgs,
 	Run: func(cmd *cobra.Command, args []string) {
-		j, err := newJournal(args)
+		if templateFile != "" && splitDir != "" {
+			log.Fatal("--template cannot be used with --split-dir")
+		}
+
+		j, err := newJournal(cmd.Context(), args)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		opts, err := dateOptions()
+		if err != nil {
+			log.Fatal(err)
+		}
+		if order != string(journal.Ascending) && order != string(journal.Descending) {
+			log.Fatalf("unknown order: %s", order)
+		}
+		if limit < 0 || offset < 0 {
+			log.Fatal("limit and offset must not be negative")
+		}
+
+		weekday, err := journal.ParseWeekday(weekStart)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		opts = append(opts,
+			journal.HeadingLevel(level),
+			journal.ExcerptLength(excerpt),
+			journal.GroupBy(journal.Grouping(groupBy)),
+			journal.WeekStart(weekday),
+			journal.EntryOrder(journal.Order(order)),
+			journal.Limit(limit),
+			journal.Offset(offset),
+			journal.YearLayout(yearLayout),
+			journal.MonthLayout(monthLayout),
+			journal.DayLayout(dayLayout),
+		)
+		switch {
+		case templateFile != "":
+			err = writeTemplate(os.Stdout, j, opts...)
+		case splitDir != "":
+			err = j.WriteTimelineSplit(splitDir, opts...)
+		default:
+			err = j.WriteTimeline(os.Stdout, opts...)
+		}
 		if err != nil {
 			log.Fatal(err)
 		}
-		j.WriteTimeline(os.Stdout, journal.HeadingLevel(level))
 	},
 }
@@ -0,0 +1,344 @@
+// Package dayone imports entries from Day One JSON exports into a journal.
+package dayone
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/taylorskalyo/markdown-journal/journal"
+	"github.com/taylorskalyo/markdown-journal/markdown/extension"
+)
+
+// AttachmentDir is the directory, relative to the output directory, that photos
+// are copied to.
+const AttachmentDir = "attachments"
+
+// maxSlugLength is the maximum length, in characters, of the slug in an entry's
+// file name.
+const maxSlugLength = 50
+
+// rePhoto matches references to photos in an entry's text.
+var rePhoto = regexp.MustCompile(`dayone-moment://([0-9A-Za-z-]+)`)
+
+// reImage and reLink match markdown images and links, with the text of a link
+// as the first submatch.
+var (
+	reImage = regexp.MustCompile(`!\[[^\]]*\]\([^)]*\)`)
+	reLink  = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+)
+
+// Export is the contents of a Day One JSON export.
+type Export struct {
+	Entries []Entry `json:"entries"`
+}
+
+// Entry is a Day One entry.
+type Entry struct {
+	UUID         string    `json:"uuid"`
+	CreationDate time.Time `json:"creationDate"`
+	TimeZone     string    `json:"timeZone"`
+	Text         string    `json:"text"`
+	Tags         []string  `json:"tags"`
+	Location     *Location `json:"location"`
+	Photos       []Photo   `json:"photos"`
+}
+
+// Location is where a Day One entry was written.
+type Location struct {
+	PlaceName          string  `json:"placeName"`
+	LocalityName       string  `json:"localityName"`
+	AdministrativeArea string  `json:"administrativeArea"`
+	Country            string  `json:"country"`
+	Latitude           float64 `json:"latitude"`
+	Longitude          float64 `json:"longitude"`
+}
+
+// Photo is a photo attached to a Day One entry. Exports store photos in a
+// "photos" directory next to the JSON file, named by their MD5 checksum.
+type Photo struct {
+	Identifier string `json:"identifier"`
+	MD5        string `json:"md5"`
+	Type       string `json:"type"`
+}
+
+// ReadExport reads a Day One JSON export.
+func ReadExport(r io.Reader) (export Export, err error) {
+	err = json.NewDecoder(r).Decode(&export)
+
+	return export, err
+}
+
+// Import converts the entries of the Day One export in file into journal
+// entries in dir and returns the names of the files written. Photos are copied
+// into an attachments directory in dir.
+//
+// Existing files are never overwritten. An entry whose file already exists, or
+// that would have the same file name as an earlier entry, is skipped, as is an
+// entry whose file cannot be written. Skipped entries and other problems that
+// do not prevent the remaining entries from being imported are returned as a
+// journal.Diagnostics error.
+func Import(file, dir string) (written []string, err error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	export, err := ReadExport(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	var diagnostics journal.Diagnostics
+	photoDir := filepath.Join(filepath.Dir(file), "photos")
+	for _, e := range export.Entries {
+		name, err := e.FileName()
+		if err != nil {
+			diagnostics = append(diagnostics, journal.Diagnostic{File: file, Message: fmt.Sprintf("entry %s: %s", e.UUID, err)})
+			continue
+		}
+		path := filepath.Join(dir, name)
+
+		out, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err != nil {
+			message := err.Error()
+			if os.IsExist(err) {
+				message = fmt.Sprintf("already exists; skipping Day One entry %s", e.UUID)
+			}
+			diagnostics = append(diagnostics, journal.Diagnostic{File: path, Message: message})
+			continue
+		}
+		text, problems := e.Markdown()
+		for _, p := range problems {
+			diagnostics = append(diagnostics, journal.Diagnostic{File: path, Message: p})
+		}
+		_, err = out.WriteString(text)
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			// Remove the partial file, so that it is imported on the next run
+			// rather than reported as existing.
+			os.Remove(path)
+			diagnostics = append(diagnostics, journal.Diagnostic{File: path, Message: err.Error()})
+			continue
+		}
+		written = append(written, path)
+
+		for _, photo := range e.Photos {
+			if err := copyPhoto(photoDir, filepath.Join(dir, AttachmentDir), photo); err != nil {
+				diagnostics = append(diagnostics, journal.Diagnostic{File: path, Message: err.Error()})
+			}
+		}
+	}
+
+	if len(diagnostics) > 0 {
+		return written, diagnostics
+	}
+
+	return written, nil
+}
+
+// Time returns the time the entry was created, in the time zone it was written
+// in, if known.
+func (e Entry) Time() time.Time {
+	if loc, err := time.LoadLocation(e.TimeZone); err == nil && e.TimeZone != "" {
+		return e.CreationDate.In(loc)
+	}
+
+	return e.CreationDate
+}
+
+// FileName returns the name of the journal entry file for the entry: its date
+// followed by a slug of its first line of text, skipping photos, e.g.
+// 2006-01-02-first-line.md.
+func (e Entry) FileName() (string, error) {
+	name := e.Time().Format("2006-01-02")
+	if slug := slugify(e.title()); slug != "" {
+		name += "-" + slug
+	}
+	name += ".md"
+
+	if _, err := journal.NewEntry(name); err != nil {
+		return "", fmt.Errorf("%s: %w", name, err)
+	}
+
+	return name, nil
+}
+
+// Markdown returns the contents of the journal entry for the entry: front
+// matter with its creation time and location, its text with photo references
+// replaced by links to attachments, and its tags as labels. Tags that cannot
+// be converted to labels are described in problems.
+func (e Entry) Markdown() (text string, problems []string) {
+	var b strings.Builder
+
+	b.WriteString("---\n")
+	fmt.Fprintf(&b, "created: %s\n", e.Time().Format(time.RFC3339))
+	if l := e.Location; l != nil {
+		if place := l.String(); place != "" {
+			fmt.Fprintf(&b, "location: %s\n", strconv.Quote(place))
+		}
+		fmt.Fprintf(&b, "latitude: %s\n", strconv.FormatFloat(l.Latitude, 'f', -1, 64))
+		fmt.Fprintf(&b, "longitude: %s\n", strconv.FormatFloat(l.Longitude, 'f', -1, 64))
+	}
+	if e.UUID != "" {
+		fmt.Fprintf(&b, "dayone: %s\n", e.UUID)
+	}
+	b.WriteString("---\n\n")
+
+	photos := map[string]Photo{}
+	for _, p := range e.Photos {
+		photos[p.Identifier] = p
+	}
+	body := rePhoto.ReplaceAllStringFunc(e.Text, func(m string) string {
+		p, ok := photos[rePhoto.FindStringSubmatch(m)[1]]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("photo %s is not in the export", m))
+			return m
+		}
+		return AttachmentDir + "/" + p.fileName()
+	})
+	if body = strings.TrimRight(strings.TrimLeft(body, "\n"), " \t\n"); body != "" {
+		b.WriteString(body + "\n")
+	}
+
+	var labels []string
+	seen := map[string]bool{}
+	for _, tag := range e.Tags {
+		label := Label(tag)
+		if label == "" {
+			problems = append(problems, fmt.Sprintf("tag %q cannot be converted to a label", tag))
+			continue
+		}
+		if !seen[label] {
+			seen[label] = true
+			labels = append(labels, ":"+label+":")
+		}
+	}
+	if len(labels) > 0 {
+		if body != "" {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "%s\n", strings.Join(labels, " "))
+	}
+
+	return b.String(), problems
+}
+
+// String returns the place name and address of the location, separated by
+// commas.
+func (l Location) String() string {
+	var parts []string
+	for _, part := range []string{l.PlaceName, l.LocalityName, l.AdministrativeArea, l.Country} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// Label converts a Day One tag to a label name. Runs of characters that are not
+// allowed in labels are replaced with dashes, e.g. "Road Trip/2019" becomes
+// "Road-Trip-2019". It returns an empty string if the tag has no characters
+// allowed in labels.
+func Label(tag string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range tag {
+		if !extension.IsLabel(string(r)) {
+			dash = b.Len() > 0
+			continue
+		}
+		if dash {
+			b.WriteRune('-')
+			dash = false
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// title returns the first line of the entry's text that has any text other
+// than images, e.g. photos, without heading markers. Links are replaced by
+// their text.
+func (e Entry) title() string {
+	for _, line := range strings.Split(e.Text, "\n") {
+		line = reLink.ReplaceAllString(reImage.ReplaceAllString(line, ""), "$1")
+		if line = strings.TrimSpace(strings.TrimLeft(line, "# ")); line != "" {
+			return line
+		}
+	}
+
+	return ""
+}
+
+// slugify returns a lowercase slug of the letters and digits in s, separated
+// by dashes and truncated to maxSlugLength characters.
+func slugify(s string) string {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	slug := ""
+	for _, word := range words {
+		next := word
+		if slug != "" {
+			next = slug + "-" + word
+		}
+		if len([]rune(next)) > maxSlugLength {
+			if slug == "" {
+				slug = string([]rune(word)[:maxSlugLength])
+			}
+			break
+		}
+		slug = next
+	}
+
+	return slug
+}
+
+// fileName returns the name of the photo's file in the export and in the
+// attachments directory.
+func (p Photo) fileName() string {
+	ext := p.Type
+	if ext == "" {
+		ext = "jpeg"
+	}
+
+	return p.MD5 + "." + ext
+}
+
+// copyPhoto copies a photo from the export's photo directory to dir. A photo
+// that has already been copied is not copied again.
+func copyPhoto(photoDir, dir string, p Photo) error {
+	dst := filepath.Join(dir, p.fileName())
+	if _, err := os.Stat(dst); err == nil {
+		return nil
+	}
+
+	source, err := ioutil.ReadFile(filepath.Join(photoDir, p.fileName()))
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(dst, source, 0644)
+}
@@ -0,0 +1,163 @@
+package dayone
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/taylorskalyo/markdown-journal/journal"
+)
+
+const testExport = `{
+  "metadata": {"version": "1.0"},
+  "entries": [
+    {
+      "uuid": "A1",
+      "creationDate": "2020-03-01T02:30:00Z",
+      "timeZone": "America/New_York",
+      "text": "# Road Trip: Day 1!\n\n![](dayone-moment://P1)\n",
+      "tags": ["Road Trip/2020", "work", "🎉"],
+      "location": {"placeName": "Diner", "localityName": "Albany", "country": "United States", "latitude": 42.65, "longitude": -73.75},
+      "photos": [{"identifier": "P1", "md5": "abc123", "type": "jpeg"}]
+    },
+    {
+      "uuid": "B2",
+      "creationDate": "2020-02-29T12:00:00Z",
+      "text": "Road trip - day 1",
+      "tags": []
+    },
+    {
+      "uuid": "C3",
+      "creationDate": "2020-03-02T12:00:00Z",
+      "text": "",
+      "tags": ["x"]
+    }
+  ]
+}`
+
+func TestLabel(t *testing.T) {
+	cases := map[string]string{
+		"work":           "work",
+		"Road Trip/2020": "Road-Trip-2020",
+		"  a & b  ":      "a-b",
+		"café":           "café",
+		"🎉":              "",
+	}
+
+	for tag, expected := range cases {
+		if actual := Label(tag); actual != expected {
+			t.Errorf("%q: expected %q, actual %q", tag, expected, actual)
+		}
+	}
+}
+
+func TestEntryFileName(t *testing.T) {
+	date := time.Date(2020, time.March, 1, 2, 30, 0, 0, time.UTC)
+	cases := []struct {
+		entry    Entry
+		expected string
+	}{
+		{Entry{CreationDate: date, Text: "# Hello, World!\n\nBody"}, "2020-03-01-hello-world.md"},
+		{Entry{CreationDate: date, TimeZone: "America/New_York", Text: "Hello"}, "2020-02-29-hello.md"},
+		{Entry{CreationDate: date, Text: "\n\n"}, "2020-03-01.md"},
+		{Entry{CreationDate: date, Text: "![](dayone-moment://P1)\n\nSunset at the [beach](https://example.com)"}, "2020-03-01-sunset-at-the-beach.md"},
+		{Entry{CreationDate: date, Text: "![](dayone-moment://P1)"}, "2020-03-01.md"},
+		{Entry{CreationDate: date, Text: strings.Repeat("word ", 20)}, "2020-03-01-" + strings.TrimSuffix(strings.Repeat("word-", 10), "-") + ".md"},
+	}
+
+	for _, tc := range cases {
+		actual, err := tc.entry.FileName()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual != tc.expected {
+			t.Errorf("%q: expected %q, actual %q", tc.entry.Text, tc.expected, actual)
+		}
+	}
+}
+
+func TestImport(t *testing.T) {
+	dir := t.TempDir()
+	export := filepath.Join(dir, "Journal.json")
+	if err := ioutil.WriteFile(export, []byte(testExport), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "photos"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "photos", "abc123.jpeg"), []byte("photo"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out := filepath.Join(dir, "out")
+	written, err := Import(export, out)
+
+	first := filepath.Join(out, "2020-02-29-road-trip-day-1.md")
+	if expected := []string{first, filepath.Join(out, "2020-03-02.md")}; !reflect.DeepEqual(written, expected) {
+		t.Errorf("expected %v, actual %v", expected, written)
+	}
+
+	var diagnostics journal.Diagnostics
+	if !errors.As(err, &diagnostics) || len(diagnostics) != 2 {
+		t.Fatalf("expected 2 diagnostics, actual %v", err)
+	}
+	if d := diagnostics[0]; d.File != first || !strings.Contains(d.Message, `"🎉"`) {
+		t.Errorf("expected a diagnostic for the tag, actual %s", d)
+	}
+	if d := diagnostics[1]; d.File != first || !strings.Contains(d.Message, "already exists") {
+		t.Errorf("expected a diagnostic for the collision, actual %s", d)
+	}
+
+	source, err := ioutil.ReadFile(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `---
+created: 2020-02-29T21:30:00-05:00
+location: "Diner, Albany, United States"
+latitude: 42.65
+longitude: -73.75
+dayone: A1
+---
+
+# Road Trip: Day 1!
+
+![](attachments/abc123.jpeg)
+
+:Road-Trip-2020: :work:
+`
+	if actual := string(source); actual != expected {
+		t.Errorf("expected:\n%s\nactual:\n%s", expected, actual)
+	}
+	if _, err := os.Stat(filepath.Join(out, AttachmentDir, "abc123.jpeg")); err != nil {
+		t.Error(err)
+	}
+
+	e, err := journal.NewFileParser().ParseEntry(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Title() != "Road Trip: Day 1!" || e.Headings[0].Line != 9 {
+		t.Errorf("expected front matter to be skipped, actual %+v", e.Headings)
+	}
+	if labels := e.Labels(); !reflect.DeepEqual(labels, []string{"Road-Trip-2020", "work"}) {
+		t.Errorf("expected tags to be labels, actual %v", labels)
+	}
+
+	// Importing again reports every entry instead of overwriting it.
+	if err := ioutil.WriteFile(first, []byte("edited"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	written, err = Import(export, out)
+	if len(written) != 0 || !errors.As(err, &diagnostics) || len(diagnostics) != 3 {
+		t.Errorf("expected 3 collisions, actual %v %v", written, err)
+	}
+	if source, _ := ioutil.ReadFile(first); string(source) != "edited" {
+		t.Error("expected existing file not to be overwritten")
+	}
+}
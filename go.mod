@@ -5,4 +5,5 @@ go 1.16
 require (
 	github.com/spf13/cobra v1.8.1
 	github.com/yuin/goldmark v1.7.4
+	golang.org/x/text v0.13.0
 )
@@ -0,0 +1,171 @@
+package journal
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// CalendarFormat is the output format of the calendar view.
+type CalendarFormat string
+
+// Supported calendar formats.
+const (
+	CalendarMarkdown CalendarFormat = "markdown"
+	CalendarText     CalendarFormat = "text"
+)
+
+// WriteCalendar generates a calendar grid for each month from the month of
+// from through the month of to, marking days that have entries, and writes the
+// result to a writer.
+func (j Journal) WriteCalendar(w io.Writer, from, to time.Time, setters ...WriterOption) error {
+	opts := newWriterOptions(setters)
+
+	days := map[string][]Entry{}
+	for _, entry := range j.Entries {
+		key := entry.Time.Format(dateFormat)
+		days[key] = append(days[key], entry)
+	}
+
+	// Entries are listed oldest first within a day.
+	for _, entries := range days {
+		for l, r := 0, len(entries)-1; l < r; l, r = l+1, r-1 {
+			entries[l], entries[r] = entries[r], entries[l]
+		}
+	}
+
+	month := monthStart(from)
+	for !month.After(to) {
+		var err error
+		switch opts.CalendarFormat {
+		case CalendarMarkdown, "":
+			err = opts.writeMarkdownMonth(w, month, days)
+		case CalendarText:
+			err = opts.writeTextMonth(w, month, days)
+		default:
+			err = fmt.Errorf("unknown calendar format: %s", opts.CalendarFormat)
+		}
+		if err != nil {
+			return err
+		}
+
+		month = month.AddDate(0, 1, 0)
+	}
+
+	return nil
+}
+
+// weeks returns the days of the month containing t, split into weeks. Days
+// outside of the month are zero.
+func (opts WriterOptions) weeks(month time.Time) (weeks [][7]time.Time) {
+	var week [7]time.Time
+
+	for day := month; day.Month() == month.Month(); day = day.AddDate(0, 0, 1) {
+		i := (int(day.Weekday()) - int(opts.WeekStart) + 7) % 7
+		week[i] = day
+		if i == 6 {
+			weeks = append(weeks, week)
+			week = [7]time.Time{}
+		}
+	}
+	if week != [7]time.Time{} {
+		weeks = append(weeks, week)
+	}
+
+	return weeks
+}
+
+// weekdays returns the abbreviated weekday names, beginning with WeekStart.
+func (opts WriterOptions) weekdays() (names [7]string) {
+	for i := range names {
+		names[i] = opts.Locale.ShortDays[(int(opts.WeekStart)+i)%7]
+	}
+
+	return names
+}
+
+func (opts WriterOptions) writeMarkdownMonth(w io.Writer, month time.Time, days map[string][]Entry) error {
+	baseHeadingDelim := strings.Repeat("#", opts.Level)
+	fmt.Fprintf(w, "\n%s %s\n\n", baseHeadingDelim, opts.Locale.Format(month, "January 2006"))
+
+	names := opts.weekdays()
+	fmt.Fprintf(w, "| %s |\n", strings.Join(names[:], " | "))
+	fmt.Fprintf(w, "|%s\n", strings.Repeat(" --- |", 7))
+
+	for _, week := range opts.weeks(month) {
+		cells := make([]string, 7)
+		for i, day := range week {
+			if day.IsZero() {
+				continue
+			}
+
+			entries := days[day.Format(dateFormat)]
+			switch len(entries) {
+			case 0:
+				cells[i] = fmt.Sprintf("%d", day.Day())
+			case 1:
+				cells[i] = fmt.Sprintf("[**%d**](%s)", day.Day(), opts.link(entries[0].File))
+			default:
+				links := []string{fmt.Sprintf("**%d**", day.Day())}
+				for n, entry := range entries {
+					links = append(links, fmt.Sprintf("[%d](%s)", n+1, opts.link(entry.File)))
+				}
+				cells[i] = strings.Join(links, " ")
+			}
+		}
+		fmt.Fprintf(w, "| %s |\n", strings.Join(cells, " | "))
+	}
+
+	return nil
+}
+
+func (opts WriterOptions) writeTextMonth(w io.Writer, month time.Time, days map[string][]Entry) error {
+	const width = 7*4 - 1
+
+	title := opts.Locale.Format(month, "January 2006")
+	if pad := (width - utf8.RuneCountInString(title)) / 2; pad > 0 {
+		title = strings.Repeat(" ", pad) + title
+	}
+	fmt.Fprintf(w, "\n%s\n", title)
+
+	var header []string
+	for _, name := range opts.weekdays() {
+		header = append(header, fmt.Sprintf("%-3.3s", name))
+	}
+	fmt.Fprintf(w, "%s\n", strings.TrimRight(strings.Join(header, " "), " "))
+
+	var listed []Entry
+	for _, week := range opts.weeks(month) {
+		cells := make([]string, 7)
+		for i, day := range week {
+			if day.IsZero() {
+				cells[i] = "   "
+				continue
+			}
+
+			entries := days[day.Format(dateFormat)]
+			marker := " "
+			if len(entries) > 0 {
+				marker = "*"
+			}
+			cells[i] = fmt.Sprintf("%2d%s", day.Day(), marker)
+			listed = append(listed, entries...)
+		}
+		fmt.Fprintf(w, "%s\n", strings.TrimRight(strings.Join(cells, " "), " "))
+	}
+
+	if len(listed) > 0 {
+		fmt.Fprintln(w)
+	}
+	for _, entry := range listed {
+		fmt.Fprintf(w, "%s %s", opts.Locale.Format(entry.Time, opts.DayLayout), opts.link(entry.File))
+		if title := entry.Title(); title != "" {
+			fmt.Fprintf(w, " - %s", title)
+		}
+		fmt.Fprintln(w)
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package journal
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/taylorskalyo/markdown-journal/ctags"
+)
+
+func TestWriteCalendar(t *testing.T) {
+	format := `
+============= case %s ================
+Expected Output:
+----------
+%v
+Actual Output:
+----------
+%v
+`
+
+	input := `
+Retro	diary/2024-02-29-retro.md	1;"	kind:title	line:1
+Leap Day	diary/2024-02-29.md	1;"	kind:title	line:1
+Start	diary/2024-02-01.md	1;"	kind:title	line:1
+`
+
+	cases := []struct {
+		name     string
+		opts     []WriterOption
+		expected string
+	}{
+		{
+			`markdown`,
+			nil,
+			`
+# February 2024
+
+| Mon | Tue | Wed | Thu | Fri | Sat | Sun |
+| --- | --- | --- | --- | --- | --- | --- |
+|  |  |  | [**1**](diary/2024-02-01.md) | 2 | 3 | 4 |
+| 5 | 6 | 7 | 8 | 9 | 10 | 11 |
+| 12 | 13 | 14 | 15 | 16 | 17 | 18 |
+| 19 | 20 | 21 | 22 | 23 | 24 | 25 |
+| 26 | 27 | 28 | **29** [1](diary/2024-02-29-retro.md) [2](diary/2024-02-29.md) |  |  |  |
+			`,
+		},
+		{
+			`text starting sunday`,
+			[]WriterOption{Calendar(CalendarText), WeekStart(time.Sunday)},
+			`
+       February 2024
+Sun Mon Tue Wed Thu Fri Sat
+                 1*  2   3
+ 4   5   6   7   8   9  10
+11  12  13  14  15  16  17
+18  19  20  21  22  23  24
+25  26  27  28  29*
+
+01 Thu diary/2024-02-01.md - Start
+29 Thu diary/2024-02-29-retro.md - Retro
+29 Thu diary/2024-02-29.md - Leap Day
+			`,
+		},
+	}
+
+	month := time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)
+	for _, tc := range cases {
+		var b bytes.Buffer
+
+		r := ctags.NewReader(strings.NewReader(input))
+		j := NewJournal(r.ReadAll())
+		if err := j.WriteCalendar(&b, month, month, tc.opts...); err != nil {
+			t.Errorf("case %s: %v", tc.name, err)
+		}
+		actual := strings.TrimSpace(b.String())
+		expected := strings.TrimSpace(tc.expected)
+		if actual != expected {
+			t.Errorf(format, tc.name, expected, actual)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package journal
+
+import (
+	"fmt"
+	"os"
+	"sort"
+)
+
+// Problem is an issue found in a journal by Check.
+type Problem struct {
+	File    string
+	Line    int
+	Message string
+}
+
+// String returns the problem in "file:line: message" format.
+func (p Problem) String() string {
+	return fmt.Sprintf("%s:%d: %s", p.File, p.Line, p.Message)
+}
+
+// Check looks for likely mistakes in the journal: links to missing files or
+// entries, labels used only once, labels that differ only in case or in the use
+// of dashes and underscores, entries without headings, and entries with the
+// same title. Problems are sorted by file and line.
+//
+// Files that look like entries but do not have a valid date (e.g.
+// 2024-02-30.md) are not part of the journal; they are reported by Files and
+// Load as Diagnostics instead.
+func (j Journal) Check() []Problem {
+	problems := append(j.CheckFiles(), j.CheckEntries()...)
+	SortProblems(problems)
+
+	return problems
+}
+
+// CheckFiles reports markdown links in the given files to files that do not
+// exist. If no files are given, the links in every entry are checked. Unlike
+// the rest of Check, this looks up each link's target on the file system.
+func (j Journal) CheckFiles(files ...string) (problems []Problem) {
+	checked := map[string]bool{}
+	for _, file := range files {
+		checked[file] = true
+	}
+
+	for _, link := range j.Links {
+		if link.Style == WikiLink || (len(files) > 0 && !checked[link.File]) {
+			continue
+		}
+
+		if _, err := os.Stat(link.Target); err != nil {
+			problems = append(problems, Problem{link.File, link.Line, fmt.Sprintf("link to missing file: %s", link.Destination)})
+		}
+	}
+
+	SortProblems(problems)
+
+	return problems
+}
+
+// CheckEntries reports the problems found by Check that can be found without
+// looking at the file system, i.e. all but links to missing files.
+func (j Journal) CheckEntries() (problems []Problem) {
+	r := newLinkResolver(j.Entries)
+	for _, link := range j.Links {
+		if link.Style != WikiLink {
+			continue
+		}
+
+		if _, ok := r.resolve(link); !ok {
+			problems = append(problems, Problem{link.File, link.Line, fmt.Sprintf("wiki link to missing entry: %s", link.Target)})
+		}
+	}
+
+	variants := map[string][]Label{}
+	variant := Normalization{FoldCase: true, Separators: true}
+	var keys []string
+	for _, l := range j.Labels {
+		if len(l.Occurrences) == 1 {
+			o := l.Occurrences[0]
+			problems = append(problems, Problem{o.File, o.Line, fmt.Sprintf("label used only once: %s", l.Name)})
+		}
+
+		key := variant.Key(l.Name)
+		if variants[key] == nil {
+			keys = append(keys, key)
+		}
+		variants[key] = append(variants[key], l)
+	}
+	for _, key := range keys {
+		labels := variants[key]
+		if len(labels) < 2 {
+			continue
+		}
+
+		// Report every spelling other than the most common one.
+		sort.SliceStable(labels, func(i, j int) bool {
+			return len(labels[i].Occurrences) > len(labels[j].Occurrences)
+		})
+		for _, l := range labels[1:] {
+			for _, o := range l.Occurrences {
+				message := fmt.Sprintf("label %s differs from %s only in case or separators", l.Name, labels[0].Name)
+				problems = append(problems, Problem{o.File, o.Line, message})
+			}
+		}
+	}
+
+	// Report duplicate titles on the newer entries.
+	titles := map[string]Entry{}
+	for i := len(j.Entries) - 1; i >= 0; i-- {
+		e := j.Entries[i]
+		title, line := "", 1
+		if len(e.Headings) > 0 {
+			title, line = e.Headings[0].Text, e.Headings[0].Line
+		}
+
+		if title == "" {
+			problems = append(problems, Problem{e.File, 1, "entry has no heading"})
+			continue
+		}
+
+		if first, ok := titles[title]; ok {
+			problems = append(problems, Problem{e.File, line, fmt.Sprintf("duplicate title %q (also used in %s)", title, first.File)})
+			continue
+		}
+		titles[title] = e
+	}
+
+	SortProblems(problems)
+
+	return problems
+}
+
+// SortProblems sorts problems by file and line.
+func SortProblems(problems []Problem) {
+	sort.SliceStable(problems, func(i, j int) bool {
+		a, b := problems[i], problems[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+}
@@ -0,0 +1,89 @@
+package journal
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/taylorskalyo/markdown-journal/ctags"
+)
+
+func TestCheck(t *testing.T) {
+	input := `
+Recipes	diary/2006-01-03.md	1;"	kind:title	line:1
+recipe	diary/2006-01-03.md	5;"	kind:label	line:5
+Recipe	diary/2006-01-03.md	6;"	kind:label	line:6
+meal_prep	diary/2006-01-03.md	7;"	kind:label	line:7
+meal-prep	diary/2006-01-03.md	8;"	kind:label	line:8
+meal-prep	diary/2006-01-03.md	9;"	kind:label	line:9
+2006-01-02	diary/2006-01-03.md	10;"	kind:link	line:10	style:wiki	target:2006-01-02
+missing.md	diary/2006-01-03.md	11;"	kind:link	line:11	style:markdown	target:diary/missing.md
+Recipes	diary/2007-11-30.md	1;"	kind:title	line:1
+recipe	diary/2007-11-30.md	3;"	kind:label	line:3
+typo	diary/2007-11-30.md	4;"	kind:label	line:4
+2006-01-03	diary/2007-11-30.md	5;"	kind:link	line:5	style:wiki	target:2006-01-03
+recipe	diary/2007-12-01.md	2;"	kind:label	line:2
+`
+
+	expected := `
+diary/2006-01-03.md:6: label used only once: Recipe
+diary/2006-01-03.md:6: label Recipe differs from recipe only in case or separators
+diary/2006-01-03.md:7: label used only once: meal_prep
+diary/2006-01-03.md:7: label meal_prep differs from meal-prep only in case or separators
+diary/2006-01-03.md:10: wiki link to missing entry: 2006-01-02
+diary/2006-01-03.md:11: link to missing file: missing.md
+diary/2007-11-30.md:1: duplicate title "Recipes" (also used in diary/2006-01-03.md)
+diary/2007-11-30.md:4: label used only once: typo
+diary/2007-12-01.md:1: entry has no heading
+`
+
+	r := ctags.NewReader(strings.NewReader(input))
+	j := NewJournal(r.ReadAll())
+
+	var actual []string
+	for _, p := range j.Check() {
+		actual = append(actual, p.String())
+	}
+	if strings.Join(actual, "\n") != strings.TrimSpace(expected) {
+		t.Errorf("expected:\n%s\nactual:\n%s", strings.TrimSpace(expected), strings.Join(actual, "\n"))
+	}
+}
+
+func TestCheckLocalLinks(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"2006-01-02.md": "# Links\n\n![img](missing.png) [doc](missing.pdf)\n\n![](present.png) [entry](2006-01-03.md)\n",
+		"2006-01-03.md": "# Target\n",
+		"present.png":   "",
+	}
+	var paths []string
+	for name, content := range files {
+		file := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if filepath.Ext(name) == ".md" {
+			paths = append(paths, file)
+		}
+	}
+
+	entries, err := ParseEntries(paths, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file := filepath.Join(dir, "2006-01-02.md")
+	expected := file + ":3: link to missing file: missing.png\n" +
+		file + ":3: link to missing file: missing.pdf"
+	var actual []string
+	for _, p := range FromEntries(entries).Check() {
+		actual = append(actual, p.String())
+	}
+	if strings.Join(actual, "\n") != expected {
+		t.Errorf("expected:\n%s\nactual:\n%s", expected, strings.Join(actual, "\n"))
+	}
+	if problems := FromEntries(entries).CheckFiles(filepath.Join(dir, "2006-01-03.md")); len(problems) != 0 {
+		t.Errorf("expected only the links of the given file to be checked, actual %v", problems)
+	}
+}
@@ -0,0 +1,50 @@
+package journal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Diagnostic describes a problem encountered while loading a journal that did
+// not prevent the rest of the journal from loading.
+type Diagnostic struct {
+	File string
+
+	// Line is 0 if the problem is not specific to a line.
+	Line    int
+	Message string
+}
+
+func (d Diagnostic) String() string {
+	if d.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s", d.File, d.Line, d.Message)
+	}
+
+	return fmt.Sprintf("%s: %s", d.File, d.Message)
+}
+
+// Diagnostics is a list of diagnostics. It is returned as an error by
+// functions that return partial results along with the problems encountered.
+type Diagnostics []Diagnostic
+
+func (d Diagnostics) Error() string {
+	messages := make([]string, len(d))
+	for i, diagnostic := range d {
+		messages[i] = diagnostic.String()
+	}
+
+	return strings.Join(messages, "\n")
+}
+
+// fileDiagnostic returns a diagnostic for an error concerning a file. The file
+// name is not repeated in the message.
+func fileDiagnostic(file string, err error) Diagnostic {
+	var pathErr *os.PathError
+	if errors.As(err, &pathErr) && pathErr.Path == file {
+		return Diagnostic{File: file, Message: fmt.Sprintf("%s: %v", pathErr.Op, pathErr.Err)}
+	}
+
+	return Diagnostic{File: file, Message: err.Error()}
+}
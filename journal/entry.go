@@ -11,15 +11,26 @@ import (
 var errNotEntry = errors.New("not a journal entry")
 var reEntryFile = regexp.MustCompile(`(\d{4}-\d{2}-\d{2})(-.*)?\.md`)
 
-// Entry is a file in the journal and its associated tags.
+// Entry is a file in the journal and its contents.
 type Entry struct {
 	Time time.Time
 	File string
 
-	// Linked list of tags.
-	FirstTag *tagNode
+	// Summary of the entry's contents, taken from its first paragraph or the
+	// content preceding a "<!-- more -->" marker.
+	Excerpt string
+
+	// Headings, labels, tasks, and links in the entry, each in increasing
+	// order by line number.
+	Headings    []Heading
+	Occurrences []LabelOccurrence
+	Tasks       []Task
+	Links       []Link
 
 	name string
+
+	// excerptLine is the line on which the excerpt begins.
+	excerptLine int
 }
 
 // NewEntry returns a new Entry.
@@ -48,10 +59,8 @@ func NewEntry(file string) (e Entry, err error) {
 // underscores (`_`) are converted to spaces, and the first letter of each word
 // is capitalized.
 func (e Entry) Title() string {
-	for n := e.FirstTag; n != nil; n = n.next {
-		if n.Kind() == "title" {
-			return n.TagName
-		}
+	if len(e.Headings) > 0 {
+		return e.Headings[0].Text
 	}
 
 	if title := e.name; title != "" {
@@ -64,3 +73,75 @@ func (e Entry) Title() string {
 
 	return ""
 }
+
+// Labels returns the canonical names of the labels in the entry, in the order
+// they first appear. Labels of an entry that is not part of a journal are
+// named as written.
+func (e Entry) Labels() (labels []string) {
+	seen := map[string]bool{}
+	for _, o := range e.Occurrences {
+		if name := o.label(); !seen[name] {
+			seen[name] = true
+			labels = append(labels, name)
+		}
+	}
+
+	return labels
+}
+
+// LabelSet returns the set of canonical names of the labels in the entry.
+func (e Entry) LabelSet() map[string]bool {
+	set := map[string]bool{}
+	for _, name := range e.Labels() {
+		set[name] = true
+	}
+
+	return set
+}
+
+// Walk calls fn for each heading and label occurrence in the entry, in
+// increasing order by line number, headings first. Walk stops if fn returns
+// false.
+func (e Entry) Walk(fn func(n Node) bool) {
+	for h, l := 0, 0; h < len(e.Headings) || l < len(e.Occurrences); {
+		var n Node
+		if l == len(e.Occurrences) || h < len(e.Headings) && e.Headings[h].Line <= e.Occurrences[l].Line {
+			heading := e.Headings[h]
+			n.Heading = &heading
+			h++
+		} else {
+			label := e.Occurrences[l]
+			n.Label = &label
+			l++
+		}
+
+		if !fn(n) {
+			return
+		}
+	}
+}
+
+// Section returns the section of the entry in which a label occurs: the
+// nearest heading at or before the label, and the content of that heading's
+// section. It returns false if the label is not part of the entry.
+func (e Entry) Section(o LabelOccurrence) (s Section, ok bool) {
+	for _, other := range e.Occurrences {
+		if other.Name == o.Name && other.Line == o.Line {
+			ok = true
+			break
+		}
+	}
+	if !ok {
+		return s, false
+	}
+
+	for _, h := range e.Headings {
+		if h.Line > o.Line {
+			break
+		}
+		s.Heading = h
+	}
+	s.Lines = o.Section
+
+	return s, true
+}
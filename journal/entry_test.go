@@ -0,0 +1,98 @@
+package journal
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func parseTestEntry(t *testing.T, file, source string) Entry {
+	t.Helper()
+
+	e, err := NewEntry(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e, err = NewFileParser().parse(e, []byte(source)); err != nil {
+		t.Fatal(err)
+	}
+
+	return FromEntries([]Entry{e}).Entries[0]
+}
+
+const testEntry = `:intro:
+
+# Day :work:
+
+:a:
+
+## Lunch
+
+:food: :a:
+
+## Evening
+`
+
+func TestEntryWalk(t *testing.T) {
+	e := parseTestEntry(t, "2006-01-02.md", testEntry)
+
+	var nodes []string
+	e.Walk(func(n Node) bool {
+		if n.Heading != nil {
+			nodes = append(nodes, fmt.Sprintf("h%d %s:%d", n.Heading.Level, n.Heading.Text, n.Heading.Line))
+		} else {
+			nodes = append(nodes, fmt.Sprintf("%s:%d", n.Label.Name, n.Label.Line))
+		}
+		return true
+	})
+
+	expected := "intro:1, h1 Day work:3, work:3, a:5, h2 Lunch:7, food:9, a:9, h2 Evening:11"
+	if actual := strings.Join(nodes, ", "); actual != expected {
+		t.Errorf("expected %q, actual %q", expected, actual)
+	}
+
+	var n int
+	e.Walk(func(Node) bool {
+		n++
+		return n < 3
+	})
+	if n != 3 {
+		t.Errorf("expected walk to stop after 3 nodes, visited %d", n)
+	}
+}
+
+func TestEntrySection(t *testing.T) {
+	e := parseTestEntry(t, "2006-01-02.md", testEntry)
+
+	cases := []struct {
+		label    int
+		expected Section
+	}{
+		{0, Section{Lines: LineRange{1, 2}}},
+		{1, Section{Heading{"Day work", 1, 3}, LineRange{4, 11}}},
+		{2, Section{Heading{"Day work", 1, 3}, LineRange{4, 11}}},
+		{3, Section{Heading{"Lunch", 2, 7}, LineRange{8, 10}}},
+	}
+
+	for _, tc := range cases {
+		o := e.Occurrences[tc.label]
+		s, ok := e.Section(o)
+		if !ok || !reflect.DeepEqual(s, tc.expected) {
+			t.Errorf("%s:%d: expected %+v, actual %+v", o.Name, o.Line, tc.expected, s)
+		}
+	}
+
+	if _, ok := e.Section(LabelOccurrence{Name: "missing", Line: 1}); ok {
+		t.Error("expected no section for a label not in the entry")
+	}
+}
+
+func TestEntryLabelSet(t *testing.T) {
+	e := parseTestEntry(t, "2006-01-02.md", testEntry)
+
+	expected := map[string]bool{"intro": true, "work": true, "a": true, "food": true}
+	if actual := e.LabelSet(); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, actual %v", expected, actual)
+	}
+}
@@ -0,0 +1,163 @@
+package journal
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"time"
+)
+
+var (
+	heatmapBlocks = []string{"·", "░", "▒", "▓", "█"}
+	heatmapColors = []string{"#ebedf0", "#9be9a8", "#40c463", "#30a14e", "#216e39"}
+)
+
+// heatmapCell is a single day in a heatmap.
+type heatmapCell struct {
+	Day     time.Time
+	Words   int
+	InRange bool
+}
+
+// heatmap arranges the days from from through to into columns of weeks. It
+// returns the columns and the largest number of words written on a day in
+// that range.
+func (s Stats) heatmap(from, to time.Time, weekStart time.Weekday) (weeks [][7]heatmapCell, max int) {
+	words := map[string]int{}
+	for _, entry := range s.Entries {
+		words[entry.Time.Format(dateFormat)] += entry.Words
+	}
+
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	to = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	start, _ := weekOf(from, weekStart)
+
+	for day := start; !day.After(to); {
+		var week [7]heatmapCell
+		for i := range week {
+			week[i] = heatmapCell{
+				Day:     day,
+				Words:   words[day.Format(dateFormat)],
+				InRange: !day.Before(from) && !day.After(to),
+			}
+			if week[i].InRange && week[i].Words > max {
+				max = week[i].Words
+			}
+			day = day.AddDate(0, 0, 1)
+		}
+		weeks = append(weeks, week)
+	}
+
+	return weeks, max
+}
+
+// heatLevel returns the intensity, from 0 to 4, of a day with the given number
+// of words relative to the maximum. Any words give at least level 1, and the
+// maximum gives level 4.
+func heatLevel(words, max int) int {
+	if words <= 0 || max <= 0 {
+		return 0
+	}
+	if words >= max {
+		return 4
+	}
+
+	return (4*words + max - 1) / max
+}
+
+// WriteHeatmap writes a contribution heatmap of the words written each day from
+// from through to. Each row is a day of the week and each column is a week.
+func (s Stats) WriteHeatmap(w io.Writer, from, to time.Time, setters ...WriterOption) error {
+	opts := newWriterOptions(setters)
+	weeks, max := s.heatmap(from, to, opts.WeekStart)
+
+	const labelWidth = 4
+
+	// Label each month above the week in which it begins, as long as there is
+	// room for it.
+	header := []rune(strings.Repeat(" ", labelWidth+len(weeks)+3))
+	next := 0
+	for i, week := range weeks {
+		for _, cell := range week {
+			if !cell.InRange || (cell.Day.Day() != 1 && i > 0) {
+				continue
+			}
+			name := []rune(opts.Locale.ShortMonths[cell.Day.Month()-1])
+			if pos := labelWidth + i; pos >= next && pos+len(name) <= len(header) {
+				copy(header[pos:], name)
+				next = pos + len(name) + 1
+			}
+			break
+		}
+	}
+	fmt.Fprintln(w, strings.TrimRight(string(header), " "))
+
+	for row := 0; row < 7; row++ {
+		name := opts.Locale.ShortDays[(int(opts.WeekStart)+row)%7]
+		fmt.Fprintf(w, "%-*.*s", labelWidth, labelWidth-1, name)
+
+		var line strings.Builder
+		for _, week := range weeks {
+			cell := week[row]
+			if !cell.InRange {
+				line.WriteString(" ")
+				continue
+			}
+			line.WriteString(heatmapBlocks[heatLevel(cell.Words, max)])
+		}
+		fmt.Fprintln(w, strings.TrimRight(line.String(), " "))
+	}
+
+	fmt.Fprintf(w, "\n%*sLess %s More\n", labelWidth, "", strings.Join(heatmapBlocks, " "))
+
+	return nil
+}
+
+// WriteHeatmapSVG writes a contribution heatmap of the words written each day
+// from from through to as an SVG image.
+func (s Stats) WriteHeatmapSVG(w io.Writer, from, to time.Time, setters ...WriterOption) error {
+	opts := newWriterOptions(setters)
+	weeks, max := s.heatmap(from, to, opts.WeekStart)
+
+	const (
+		cell   = 10
+		pitch  = 13
+		left   = 32
+		top    = 20
+		family = "sans-serif"
+	)
+
+	width := left + len(weeks)*pitch
+	height := top + 7*pitch
+	fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" font-family="%s" font-size="9">`+"\n", width, height, family)
+
+	for row := 0; row < 7; row += 2 {
+		name := opts.Locale.ShortDays[(int(opts.WeekStart)+row)%7]
+		fmt.Fprintf(w, `<text x="0" y="%d">%s</text>`+"\n", top+row*pitch+cell-1, html.EscapeString(name))
+	}
+
+	labeled := false
+	for i, week := range weeks {
+		for row, c := range week {
+			if !c.InRange {
+				continue
+			}
+
+			x, y := left+i*pitch, top+row*pitch
+			if c.Day.Day() == 1 || !labeled {
+				labeled = true
+				name := opts.Locale.ShortMonths[c.Day.Month()-1]
+				fmt.Fprintf(w, `<text x="%d" y="%d">%s</text>`+"\n", x, top-6, html.EscapeString(name))
+			}
+
+			color := heatmapColors[heatLevel(c.Words, max)]
+			fmt.Fprintf(w, `<rect x="%d" y="%d" width="%d" height="%d" rx="2" fill="%s"><title>%s: %d words</title></rect>`+"\n",
+				x, y, cell, cell, color, c.Day.Format(dateFormat), c.Words)
+		}
+	}
+
+	fmt.Fprintln(w, "</svg>")
+
+	return nil
+}
@@ -1,12 +1,15 @@
 package journal
 
 import (
+	"context"
+	"fmt"
+	"io"
 	"os"
 	"path"
 	"path/filepath"
 	"sort"
-
-	"github.com/taylorskalyo/markdown-journal/ctags"
+	"strings"
+	"time"
 )
 
 const (
@@ -19,104 +22,306 @@ const (
 // Label is a keyword that appears in a journal entry.
 type Label struct {
 	Name        string
-	Occurrences []LabelTag
-}
+	Occurrences []LabelOccurrence
 
-// LabelTag is an occurrence of a Label within a journal entry.
-type LabelTag struct {
-	*tagNode
+	// Description and metadata from the label's vocabulary declaration or
+	// note.
+	Description string
+	Metadata    map[string]string
 }
 
 // Journal is a collection of entries and labels.
 type Journal struct {
 	Entries []Entry
 	Labels  []Label
-}
+	Links   []Link
 
-// TagLines attaches the methods of sort.Interface to []ctags.TagLine.
-type TagLines []ctags.TagLine
+	// Diagnostics describes files and tags that were skipped while loading
+	// the journal, e.g. because they are not valid journal entries.
+	Diagnostics Diagnostics
+
+	options JournalOptions
+}
 
-// LabelOccurrences attaches the methods of sort.Interface to []LabelTag.
-type LabelOccurrences []LabelTag
+// LabelOccurrences attaches the methods of sort.Interface to
+// []LabelOccurrence.
+type LabelOccurrences []LabelOccurrence
 
 // WriterOptions stores options for write functions.
 type WriterOptions struct {
 	Level int
+
+	// Locale and Go layout strings used to format year, month, and day
+	// headings.
+	Locale      Locale
+	YearLayout  string
+	MonthLayout string
+	DayLayout   string
+
+	// GroupBy determines how the timeline groups entries. WeekStart is the
+	// first day of the week when grouping by GroupByWeek.
+	GroupBy   Grouping
+	WeekStart time.Weekday
+
+	// Order, Offset, and Limit determine which entries are written and in
+	// what order. A Limit of 0 means no limit.
+	Order  Order
+	Offset int
+	Limit  int
+
+	// Excerpt is the maximum number of characters of each entry's excerpt to
+	// include. If 0, excerpts are not included.
+	Excerpt int
+
+	// CalendarFormat is the output format of the calendar view.
+	CalendarFormat CalendarFormat
+
+	// Section is the maximum number of lines of each labelled section to
+	// include in the labels view. If 0, sections are not included.
+	// SectionFormat determines how they are included.
+	Section       int
+	SectionFormat SectionFormat
+
+	// LinkDir is the directory links are relative to. If empty, links are
+	// relative to the current directory.
+	LinkDir string
+
+	// Highlight determines whether previews highlight the selected line with
+	// ANSI escape codes rather than marking it with ">".
+	Highlight bool
 }
 
+// Order is the chronological order in which entries are written.
+type Order string
+
+// Supported entry orders.
+const (
+	Ascending  Order = "asc"
+	Descending Order = "desc"
+)
+
 // WriterOption appplies an option to a WriterOptions struct.
 type WriterOption func(*WriterOptions)
 
-type tagNode struct {
-	ctags.TagLine
-	next *tagNode
-	prev *tagNode
+// JournalOptions stores options for building a Journal.
+type JournalOptions struct {
+	// Vocabulary maps label aliases and deprecated names to canonical labels.
+	Vocabulary Vocabulary
+
+	// Normalization determines which label spellings are treated as the same
+	// label.
+	Normalization Normalization
+
+	// Notes describe labels, keyed by label name. A note's description takes
+	// precedence over the label's description in the vocabulary.
+	Notes map[string]LabelNote
 }
 
-// NewJournal returns a new Journal.
-func NewJournal(tags TagLines) (j Journal) {
-	var err error
-	var e Entry
-	var l Label
-	var occurrences LabelOccurrences
+// JournalOption applies an option to a JournalOptions struct.
+type JournalOption func(*JournalOptions)
 
-	sort.Sort(sort.Reverse(tags))
-	for _, tag := range tags {
-		if tag.TagFile != e.File {
-			if e.File != "" {
-				j.Entries = append(j.Entries, e)
-			}
+// NewJournal returns a new Journal built from ctags tags.
+func NewJournal(tags TagLines, setters ...JournalOption) Journal {
+	entries, diagnostics := entriesFromTags(tags)
 
-			e, err = NewEntry(tag.TagFile)
-			if err != nil {
-				continue
-			}
-		}
+	j := FromEntries(entries, setters...)
+	j.Diagnostics = diagnostics
 
-		// Prepend tag. When done, an Entry's tags will appear in increasing order
-		// by line number, headings first.
-		n := &tagNode{TagLine: tag}
-		head := e.FirstTag
-		if head != nil {
-			head.prev = n
-			n.next = head
+	return j
+}
+
+// FromEntries returns a new Journal built from the given entries. Entries are
+// copied and sorted newest first, and the File of their labels, tasks, and
+// links is set to the entry's file. Labels are grouped using the journal
+// options.
+func FromEntries(entries []Entry, setters ...JournalOption) (j Journal) {
+	opts := &JournalOptions{}
+	for _, setter := range setters {
+		setter(opts)
+	}
+	opts.Vocabulary = opts.Vocabulary.Normalize(opts.Normalization)
+	j.options = *opts
+
+	j.Entries = append([]Entry(nil), entries...)
+	sort.SliceStable(j.Entries, func(a, b int) bool {
+		return j.Entries[a].File > j.Entries[b].File
+	})
+
+	var occurrences []*LabelOccurrence
+	for i := range j.Entries {
+		e := &j.Entries[i]
+		e.Occurrences = append([]LabelOccurrence(nil), e.Occurrences...)
+		e.Tasks = append([]Task(nil), e.Tasks...)
+		e.Links = append([]Link(nil), e.Links...)
+
+		for k := range e.Occurrences {
+			o := &e.Occurrences[k]
+			o.File = e.File
+			o.key = opts.labelKey(o.Name)
+			occurrences = append(occurrences, o)
 		}
-		e.FirstTag = n
+		for k := range e.Tasks {
+			e.Tasks[k].File = e.File
+		}
+		for k := range e.Links {
+			e.Links[k].File = e.File
+		}
+		j.Links = append(j.Links, e.Links...)
+	}
+
+	notes := map[string]LabelNote{}
+	for name, note := range opts.Notes {
+		notes[opts.labelKey(name)] = note
+	}
 
-		if tag.Kind() == "label" {
-			occurrences = append(occurrences, LabelTag{n})
+	sort.SliceStable(occurrences, func(a, b int) bool {
+		return occurrences[a].less(*occurrences[b])
+	})
+	for i := 0; i < len(occurrences); {
+		k := i + 1
+		for k < len(occurrences) && occurrences[k].key == occurrences[i].key {
+			k++
 		}
+		j.Labels = append(j.Labels, opts.label(occurrences[i:k], notes))
+		i = k
 	}
 
-	if e.File != "" {
-		j.Entries = append(j.Entries, e)
+	return j
+}
+
+// labelKey returns the key identifying the label a label name belongs to.
+func (opts JournalOptions) labelKey(name string) string {
+	c, _ := opts.Vocabulary.Canonical(name)
+
+	return opts.Normalization.Key(c)
+}
+
+// label names a label from its occurrences and describes it using the
+// vocabulary and notes, keyed by label key. Labels in the vocabulary use their
+// canonical name. Otherwise, the most common spelling is used, preferring the
+// first in sort order if there is a tie. The label's name is also set on each
+// occurrence.
+func (opts JournalOptions) label(occurrences []*LabelOccurrence, notes map[string]LabelNote) (l Label) {
+	counts := map[string]int{}
+	for _, o := range occurrences {
+		c, ok := opts.Vocabulary.Canonical(o.Name)
+		if ok {
+			l.Name = c
+			break
+		}
+		counts[c]++
+		if l.Name == "" || counts[c] > counts[l.Name] || counts[c] == counts[l.Name] && c < l.Name {
+			l.Name = c
+		}
 	}
 
-	sort.Sort(occurrences)
 	for _, o := range occurrences {
-		if o.TagName != l.Name {
-			if l.Name != "" {
-				j.Labels = append(j.Labels, l)
-			}
-			l = Label{Name: o.TagName}
+		o.Label = l.Name
+		l.Occurrences = append(l.Occurrences, *o)
+	}
+
+	l.Metadata = map[string]string{}
+	if v, ok := opts.Vocabulary.Lookup(l.Name); ok {
+		l.Description = v.Description
+		for k, v := range v.Metadata {
+			l.Metadata[k] = v
+		}
+	}
+	if note, ok := notes[l.Occurrences[0].key]; ok {
+		if note.Description != "" {
+			l.Description = note.Description
 		}
-		l.Occurrences = append(l.Occurrences, o)
+		for k, v := range note.Metadata {
+			l.Metadata[k] = v
+		}
+	}
+
+	return l
+}
+
+// FilterLabels returns a copy of the journal containing only the named labels.
+// Names are matched using the journal's vocabulary and label normalization.
+func (j Journal) FilterLabels(names []string) Journal {
+	keys := map[string]bool{}
+	for _, name := range names {
+		keys[j.options.labelKey(name)] = true
 	}
-	if l.Name != "" {
-		j.Labels = append(j.Labels, l)
+
+	labels := j.Labels
+	j.Labels = nil
+	for _, l := range labels {
+		if keys[l.Occurrences[0].key] {
+			j.Labels = append(j.Labels, l)
+		}
 	}
 
 	return j
 }
 
+// Between returns a copy of the journal containing only the entries dated
+// within the range since to until, inclusive, and the labels and links within
+// those entries. A zero time leaves that end of the range unbounded.
+func (j Journal) Between(since, until time.Time) (filtered Journal) {
+	filtered.options = j.options
+	filtered.Diagnostics = j.Diagnostics
+
+	files := map[string]bool{}
+	for _, e := range j.Entries {
+		if !since.IsZero() && e.Time.Before(since) {
+			continue
+		}
+		if !until.IsZero() && e.Time.After(until) {
+			continue
+		}
+		filtered.Entries = append(filtered.Entries, e)
+		files[e.File] = true
+	}
+
+	for _, l := range j.Links {
+		if files[l.File] {
+			filtered.Links = append(filtered.Links, l)
+		}
+	}
+
+	for _, l := range j.Labels {
+		label := l
+		label.Occurrences = nil
+		for _, o := range l.Occurrences {
+			if files[o.File] {
+				label.Occurrences = append(label.Occurrences, o)
+			}
+		}
+		if len(label.Occurrences) > 0 {
+			filtered.Labels = append(filtered.Labels, label)
+		}
+	}
+
+	return filtered
+}
+
 // Files finds journal entry files. It walks each given path checking for ones
 // that look like journal entries. It returns a list of the entries it finds.
 // If recurse is true, Files will recurse into subdirectories.
+//
+// Paths that cannot be read and files named like entries that do not have a
+// valid date (e.g. 2024-02-30.md) are skipped. The entries found are returned
+// along with a Diagnostics error describing each one.
 func Files(paths []string, recurse bool) (entries []string, err error) {
+	return files(context.Background(), paths, recurse)
+}
+
+// files is Files, stopping early with the context's error if the context is
+// done.
+func files(ctx context.Context, paths []string, recurse bool) (entries []string, err error) {
+	var diagnostics Diagnostics
 	for _, pathArg := range paths {
 		err = filepath.Walk(pathArg, func(path string, info os.FileInfo, err error) error {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
 			if err != nil {
-				return err
+				diagnostics = append(diagnostics, fileDiagnostic(path, err))
+				return nil
 			}
 
 			// Only visit a directory if it was supplied as an argument or recurse
@@ -125,9 +330,16 @@ func Files(paths []string, recurse bool) (entries []string, err error) {
 				return filepath.SkipDir
 			}
 
-			if !info.IsDir() && isJournalFile(info.Name()) {
-				entries = append(entries, path)
+			if info.IsDir() || !isJournalFile(info.Name()) {
+				return nil
+			}
+
+			if _, err := NewEntry(path); err != nil {
+				message := fmt.Sprintf("not a valid journal entry: %v", err)
+				diagnostics = append(diagnostics, Diagnostic{File: path, Message: message})
+				return nil
 			}
+			entries = append(entries, path)
 			return nil
 		})
 
@@ -135,8 +347,32 @@ func Files(paths []string, recurse bool) (entries []string, err error) {
 			return entries, err
 		}
 	}
+	if len(diagnostics) > 0 {
+		return entries, diagnostics
+	}
 
-	return entries, err
+	return entries, nil
+}
+
+// LabelVocabulary sets the Vocabulary JournalOption value.
+func LabelVocabulary(v Vocabulary) JournalOption {
+	return func(opts *JournalOptions) {
+		opts.Vocabulary = v
+	}
+}
+
+// LabelNotes sets the Notes JournalOption value.
+func LabelNotes(notes map[string]LabelNote) JournalOption {
+	return func(opts *JournalOptions) {
+		opts.Notes = notes
+	}
+}
+
+// LabelNormalization sets the Normalization JournalOption value.
+func LabelNormalization(n Normalization) JournalOption {
+	return func(opts *JournalOptions) {
+		opts.Normalization = n
+	}
 }
 
 // HeadingLevel sets the Level WriterOption value.
@@ -146,53 +382,209 @@ func HeadingLevel(level int) WriterOption {
 	}
 }
 
-func isJournalFile(file string) bool {
-	return reEntryFile.MatchString(path.Base(file))
+// DateLocale sets the Locale WriterOption value.
+func DateLocale(l Locale) WriterOption {
+	return func(opts *WriterOptions) {
+		opts.Locale = l
+	}
 }
 
-func (t TagLines) Len() int      { return len(t) }
-func (t TagLines) Swap(i, j int) { t[i], t[j] = t[j], t[i] }
+// YearLayout sets the YearLayout WriterOption value.
+func YearLayout(layout string) WriterOption {
+	return func(opts *WriterOptions) {
+		opts.YearLayout = layout
+	}
+}
 
-// Sort by tagfile then line number in increasing order. Headings appear first.
-func (t TagLines) Less(i, j int) bool {
-	a, b := t[i], t[j]
+// MonthLayout sets the MonthLayout WriterOption value.
+func MonthLayout(layout string) WriterOption {
+	return func(opts *WriterOptions) {
+		opts.MonthLayout = layout
+	}
+}
 
-	if a.TagFile != b.TagFile {
-		return a.TagFile < b.TagFile
+// DayLayout sets the DayLayout WriterOption value.
+func DayLayout(layout string) WriterOption {
+	return func(opts *WriterOptions) {
+		opts.DayLayout = layout
 	}
+}
 
-	if a.Line() != b.Line() {
-		return a.Line() < b.Line()
+// GroupBy sets the GroupBy WriterOption value.
+func GroupBy(g Grouping) WriterOption {
+	return func(opts *WriterOptions) {
+		opts.GroupBy = g
 	}
+}
 
-	return a.Kind() == "title"
+// WeekStart sets the WeekStart WriterOption value.
+func WeekStart(d time.Weekday) WriterOption {
+	return func(opts *WriterOptions) {
+		opts.WeekStart = d
+	}
 }
 
-func (lo LabelOccurrences) Len() int      { return len(lo) }
-func (lo LabelOccurrences) Swap(i, j int) { lo[i], lo[j] = lo[j], lo[i] }
+// EntryOrder sets the Order WriterOption value.
+func EntryOrder(o Order) WriterOption {
+	return func(opts *WriterOptions) {
+		opts.Order = o
+	}
+}
 
-// Sort by label name in increasing order, then tagfile and line number in
-// decreasing order.
-func (lo LabelOccurrences) Less(i, j int) bool {
-	a, b := lo[i], lo[j]
+// Offset sets the Offset WriterOption value.
+func Offset(n int) WriterOption {
+	return func(opts *WriterOptions) {
+		opts.Offset = n
+	}
+}
+
+// Limit sets the Limit WriterOption value.
+func Limit(n int) WriterOption {
+	return func(opts *WriterOptions) {
+		opts.Limit = n
+	}
+}
+
+// ExcerptLength sets the Excerpt WriterOption value.
+func ExcerptLength(n int) WriterOption {
+	return func(opts *WriterOptions) {
+		opts.Excerpt = n
+	}
+}
+
+// Calendar sets the CalendarFormat WriterOption value.
+func Calendar(f CalendarFormat) WriterOption {
+	return func(opts *WriterOptions) {
+		opts.CalendarFormat = f
+	}
+}
+
+// SectionLength sets the Section WriterOption value.
+func SectionLength(lines int) WriterOption {
+	return func(opts *WriterOptions) {
+		opts.Section = lines
+	}
+}
+
+// SectionStyle sets the SectionFormat WriterOption value.
+func SectionStyle(f SectionFormat) WriterOption {
+	return func(opts *WriterOptions) {
+		opts.SectionFormat = f
+	}
+}
+
+// LinkDir sets the LinkDir WriterOption value.
+func LinkDir(dir string) WriterOption {
+	return func(opts *WriterOptions) {
+		opts.LinkDir = dir
+	}
+}
 
-	if a.TagName != b.TagName {
-		return a.TagName < b.TagName
+// HighlightLine sets the Highlight WriterOption value.
+func HighlightLine(highlight bool) WriterOption {
+	return func(opts *WriterOptions) {
+		opts.Highlight = highlight
 	}
+}
 
-	if a.TagFile != b.TagFile {
-		return a.TagFile > b.TagFile
+// newWriterOptions returns WriterOptions with defaults applied, followed by the
+// given setters.
+func newWriterOptions(setters []WriterOption) *WriterOptions {
+	opts := &WriterOptions{
+		Level:       1,
+		Locale:      locales[DefaultLocale],
+		YearLayout:  yearFormat,
+		MonthLayout: monthFormat,
+		DayLayout:   dayFormat,
+		GroupBy:     GroupByMonth,
+		WeekStart:   time.Monday,
+		Order:       Descending,
+
+		CalendarFormat: CalendarMarkdown,
+		SectionFormat:  SectionQuote,
 	}
 
-	return a.Line() > b.Line()
+	for _, setter := range setters {
+		setter(opts)
+	}
+
+	return opts
+}
+
+func isJournalFile(file string) bool {
+	return reEntryFile.MatchString(path.Base(file))
 }
 
-func (t tagNode) section() *tagNode {
-	for n := t.prev; n != nil; n = n.prev {
-		if n.Kind() == "title" {
-			return n
+func (lo LabelOccurrences) Len() int           { return len(lo) }
+func (lo LabelOccurrences) Swap(i, j int)      { lo[i], lo[j] = lo[j], lo[i] }
+func (lo LabelOccurrences) Less(i, j int) bool { return lo[i].less(lo[j]) }
+
+// Sort by label key in increasing order, then file and line number in
+// decreasing order.
+func (o LabelOccurrence) less(other LabelOccurrence) bool {
+	if o.key != other.key {
+		return o.key < other.key
+	}
+
+	if o.File != other.File {
+		return o.File > other.File
+	}
+
+	return o.Line > other.Line
+}
+
+// entries returns the entries to write, sorted and paginated according to the
+// options.
+func (opts WriterOptions) entries(entries []Entry) []Entry {
+	sorted := make([]Entry, len(entries))
+	copy(sorted, entries)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if opts.Order == Ascending {
+			a, b = b, a
+		}
+
+		if !a.Time.Equal(b.Time) {
+			return a.Time.After(b.Time)
+		}
+
+		return a.File > b.File
+	})
+
+	if opts.Offset > 0 {
+		if opts.Offset >= len(sorted) {
+			return nil
 		}
+		sorted = sorted[opts.Offset:]
+	}
+
+	if opts.Limit > 0 && opts.Limit < len(sorted) {
+		sorted = sorted[:opts.Limit]
+	}
+
+	return sorted
+}
+
+// link returns a link to the given file, relative to LinkDir.
+func (opts WriterOptions) link(file string) string {
+	if opts.LinkDir == "" {
+		return file
+	}
+
+	return relpath(opts.LinkDir, file)
+}
+
+// writeExcerpt writes an entry's excerpt, shortened to a single line of at most
+// Excerpt characters, as a blockquote nested under a list item.
+func (opts WriterOptions) writeExcerpt(w io.Writer, excerpt string) {
+	if opts.Excerpt <= 0 || excerpt == "" {
+		return
+	}
+
+	excerpt = strings.Join(strings.Fields(excerpt), " ")
+	if runes := []rune(excerpt); len(runes) > opts.Excerpt {
+		excerpt = strings.TrimSpace(string(runes[:opts.Excerpt])) + "…"
 	}
 
-	return nil
+	fmt.Fprintf(w, "  > %s\n", excerpt)
 }
@@ -0,0 +1,153 @@
+package journal
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/taylorskalyo/markdown-journal/ctags"
+)
+
+func TestBetween(t *testing.T) {
+	input := `
+recipe	diary/2006-01-03.md	5;"	kind:label	line:5
+recipe	diary/2007-11-30.md	3;"	kind:label	line:3
+groceries	diary/2007-11-30.md	14;"	kind:label	line:14
+`
+
+	date := func(s string) time.Time {
+		d, _ := time.Parse(dateFormat, s)
+		return d
+	}
+
+	cases := []struct {
+		name           string
+		since, until   time.Time
+		expectedFiles  string
+		expectedLabels string
+	}{
+		{`unbounded`, time.Time{}, time.Time{}, `diary/2007-11-30.md diary/2006-01-03.md`, `groceries recipe`},
+		{`since`, date("2007-11-30"), time.Time{}, `diary/2007-11-30.md`, `groceries recipe`},
+		{`until`, time.Time{}, date("2007-11-29"), `diary/2006-01-03.md`, `recipe`},
+		{`empty range`, date("2006-01-04"), date("2007-11-29"), ``, ``},
+	}
+
+	for _, tc := range cases {
+		r := ctags.NewReader(strings.NewReader(input))
+		j := NewJournal(r.ReadAll()).Between(tc.since, tc.until)
+
+		var files, labels []string
+		for _, e := range j.Entries {
+			files = append(files, e.File)
+		}
+		for _, l := range j.Labels {
+			labels = append(labels, l.Name)
+		}
+
+		if actual := strings.Join(files, " "); actual != tc.expectedFiles {
+			t.Errorf("case %s: entries %q, expected %q", tc.name, actual, tc.expectedFiles)
+		}
+		if actual := strings.Join(labels, " "); actual != tc.expectedLabels {
+			t.Errorf("case %s: labels %q, expected %q", tc.name, actual, tc.expectedLabels)
+		}
+	}
+}
+
+func TestFilesDiagnostics(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"2006-01-02.md", "2006-02-30.md", "notes.md"} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	missing := filepath.Join(dir, "missing")
+
+	files, err := Files([]string{dir, missing}, false)
+	if len(files) != 1 || files[0] != filepath.Join(dir, "2006-01-02.md") {
+		t.Errorf("unexpected files: %v", files)
+	}
+
+	expected := Diagnostics{
+		{File: filepath.Join(dir, "2006-02-30.md"), Message: `not a valid journal entry: parsing time "2006-02-30": day out of range`},
+		{File: missing, Message: "lstat: no such file or directory"},
+	}
+	if fmt.Sprint(err) != fmt.Sprint(expected) {
+		t.Errorf("expected:\n%v\nactual:\n%v", expected, err)
+	}
+}
+
+func TestNewJournalDiagnostics(t *testing.T) {
+	input := `
+recipe	diary/2006-01-03.md	5;"	kind:label	line:5
+recipe	diary/2006-02-30.md	3;"	kind:label	line:3
+recipe	diary/2006-02-30.md	4;"	kind:label	line:4
+recipe	diary/notes.md	1;"	kind:label	line:1
+`
+
+	r := ctags.NewReader(strings.NewReader(input))
+	j := NewJournal(r.ReadAll())
+
+	expected := `
+diary/2006-02-30.md: not a valid journal entry: parsing time "2006-02-30": day out of range
+diary/notes.md: not a valid journal entry: not a journal entry
+`
+	if j.Diagnostics.Error() != strings.TrimSpace(expected) {
+		t.Errorf("expected:\n%s\nactual:\n%s", strings.TrimSpace(expected), j.Diagnostics.Error())
+	}
+
+	if len(j.Entries) != 1 || len(j.Labels) != 1 || len(j.Labels[0].Occurrences) != 1 {
+		t.Errorf("expected only tags of valid entries, got %d entries and labels %v", len(j.Entries), j.Labels)
+	}
+}
+
+func TestFromEntries(t *testing.T) {
+	entries := []Entry{
+		{
+			File:     "diary/2006-01-02.md",
+			Headings: []Heading{{Text: "Monday", Level: 1, Line: 1}},
+			Occurrences: []LabelOccurrence{
+				{Name: "Work", Line: 3},
+				{Name: "meal_prep", Line: 5},
+			},
+			Links: []Link{{Destination: "2006-01-03", Target: "2006-01-03", Style: WikiLink, Line: 7}},
+		},
+		{
+			File:        "diary/2006-01-03.md",
+			Occurrences: []LabelOccurrence{{Name: "work", Line: 2}},
+		},
+	}
+
+	j := FromEntries(entries, LabelNormalization(Normalization{FoldCase: true}))
+
+	if len(j.Entries) != 2 || j.Entries[0].File != "diary/2006-01-03.md" {
+		t.Fatalf("expected entries newest first, actual %+v", j.Entries)
+	}
+	if title := j.Entries[1].Title(); title != "Monday" {
+		t.Errorf("expected title Monday, actual %q", title)
+	}
+
+	var labels []string
+	for _, l := range j.Labels {
+		labels = append(labels, fmt.Sprintf("%s:%d", l.Name, len(l.Occurrences)))
+	}
+	if actual := strings.Join(labels, " "); actual != "meal_prep:1 Work:2" {
+		t.Errorf("unexpected labels %q", actual)
+	}
+	if o := j.Labels[1].Occurrences[1]; o.File != "diary/2006-01-02.md" || o.Label != "Work" {
+		t.Errorf("expected occurrence file and label to be set, actual %+v", o)
+	}
+	if actual := strings.Join(j.Entries[0].Labels(), ","); actual != "Work" {
+		t.Errorf("expected entry labels to use display name, actual %q", actual)
+	}
+
+	if len(j.Links) != 1 || j.Links[0].Location() != "diary/2006-01-02.md:7" {
+		t.Errorf("unexpected links %+v", j.Links)
+	}
+
+	if entries[1].Occurrences[0].Label != "" {
+		t.Error("expected given entries not to be modified")
+	}
+}
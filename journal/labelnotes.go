@@ -0,0 +1,97 @@
+package journal
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LabelNote describes a label. Notes are read from markdown files named after
+// the label, e.g. labels/meeting.md.
+type LabelNote struct {
+	Description string
+	Metadata    map[string]string
+}
+
+// ReadLabelNotes reads a note for each markdown file in dir, keyed by label
+// name. A note may begin with front matter of "key: value" lines between "---"
+// lines, which is stored as metadata. A heading on the first line is omitted
+// from the description. It is not an error for dir not to exist.
+func ReadLabelNotes(dir string) (map[string]LabelNote, error) {
+	notes := map[string]LabelNote{}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.md"))
+	if err != nil {
+		return notes, err
+	}
+
+	for _, file := range files {
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return notes, err
+		}
+
+		name := strings.TrimSuffix(filepath.Base(file), ".md")
+		notes[name] = parseLabelNote(string(b))
+	}
+
+	return notes, nil
+}
+
+func parseLabelNote(text string) (note LabelNote) {
+	note.Metadata = map[string]string{}
+
+	lines := splitLines(strings.ReplaceAll(text, "\r\n", "\n"))
+	metadata, n := frontMatter(lines)
+	for key, value := range metadata {
+		note.Metadata[key] = value
+	}
+	lines = lines[n:]
+
+	body := strings.TrimSpace(strings.Join(lines, ""))
+	if strings.HasPrefix(body, "#") {
+		p := strings.SplitN(body, "\n", 2)
+		body = ""
+		if len(p) == 2 {
+			body = strings.TrimSpace(p[1])
+		}
+	}
+	note.Description = body
+
+	return note
+}
+
+// frontMatter returns the metadata in the front matter at the start of lines,
+// and the number of lines the front matter spans. Front matter starts with a
+// "---" line that is directly followed by "key: value" lines, or indented lines
+// continuing them, and ends with a "---" or "..." line. If lines do not start
+// with front matter, n is 0, so that a leading thematic break or setext heading
+// underline is not mistaken for front matter.
+func frontMatter(lines []string) (metadata map[string]string, n int) {
+	if len(lines) < 3 || strings.TrimSpace(lines[0]) != "---" {
+		return nil, 0
+	}
+
+	metadata = map[string]string{}
+	for i := 1; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if i > 1 && (line == "---" || line == "...") {
+			return metadata, i + 1
+		}
+		if i > 1 && line != "" && strings.TrimLeft(lines[i], " \t") != lines[i] {
+			continue
+		}
+
+		p := strings.SplitN(line, ":", 2)
+		if len(p) != 2 || strings.TrimSpace(p[0]) == "" {
+			return nil, 0
+		}
+		metadata[strings.TrimSpace(p[0])] = strings.TrimSpace(p[1])
+	}
+
+	return nil, 0
+}
@@ -0,0 +1,108 @@
+package journal
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/taylorskalyo/markdown-journal/ctags"
+)
+
+func TestReadLabelNotes(t *testing.T) {
+	dir := t.TempDir()
+	notes := map[string]string{
+		"meeting.md": "---\ncolor: blue\nowner: ops\n---\n# Meeting\n\nTeam meetings.\n",
+		"recipe.md":  "Things to cook.\n",
+		"empty.md":   "# Empty\n",
+		"readme.txt": "Not a note.\n",
+	}
+	for name, text := range notes {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(text), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	actual, err := ReadLabelNotes(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]LabelNote{
+		"meeting": {"Team meetings.", map[string]string{"color": "blue", "owner": "ops"}},
+		"recipe":  {"Things to cook.", map[string]string{}},
+		"empty":   {"", map[string]string{}},
+	}
+	if fmt.Sprint(actual) != fmt.Sprint(expected) {
+		t.Errorf("expected %v, actual %v", expected, actual)
+	}
+
+	if _, err := ReadLabelNotes(filepath.Join(dir, "missing")); err != nil {
+		t.Errorf("expected no error for missing directory, got %v", err)
+	}
+}
+
+func TestWriteLabelsSplit(t *testing.T) {
+	input := `
+mtg	diary/2024-03-17.md	2;"	heading:Standup	kind:label	line:2
+meeting	diary/2024-03-18.md	2;"	heading:Retro	kind:label	line:2
+recipe	diary/2024-03-18.md	4;"	heading:Dinner	kind:label	line:4
+`
+
+	v, err := ReadVocabulary(strings.NewReader(testVocabulary))
+	if err != nil {
+		t.Fatal(err)
+	}
+	notes := map[string]LabelNote{
+		"recipe": {Description: "Things to cook."},
+	}
+
+	dir := filepath.Join(t.TempDir(), "labels")
+	r := ctags.NewReader(strings.NewReader(input))
+	j := NewJournal(r.ReadAll(), LabelVocabulary(v), LabelNotes(notes))
+	if err := j.WriteLabelsSplit(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	index, err := ioutil.ReadFile(filepath.Join(dir, "index.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `* [meeting](meeting.md) - 2 occurrences
+* [recipe](recipe.md) - 1 occurrence
+`
+	if string(index) != expected {
+		t.Errorf("index: got\n%s\nexpected\n%s", index, expected)
+	}
+
+	page, err := ioutil.ReadFile(filepath.Join(dir, "meeting.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	link := relpath(dir, "diary/2024-03-18.md")
+	expected = fmt.Sprintf(`
+# meeting
+
+Team meetings and one-on-ones
+
+* [Retro](%[1]s:2)
+* [Standup](%[2]s:2)
+`, link, relpath(dir, "diary/2024-03-17.md"))
+	if string(page) != expected {
+		t.Errorf("meeting.md: got\n%s\nexpected\n%s", page, expected)
+	}
+
+	page, err = ioutil.ReadFile(filepath.Join(dir, "recipe.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(page), "\nThings to cook.\n") {
+		t.Errorf("recipe.md: expected note description, got\n%s", page)
+	}
+
+	r = ctags.NewReader(strings.NewReader("index\tdiary/2024-03-17.md\t2;\"\tkind:label\tline:2\n"))
+	if err := NewJournal(r.ReadAll()).WriteLabelsSplit(dir); err == nil {
+		t.Error("expected an error for a label named index")
+	}
+}
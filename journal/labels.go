@@ -3,37 +3,164 @@ package journal
 import (
 	"fmt"
 	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"strings"
 )
 
+// SectionFormat determines how labelled sections are included in the labels
+// view.
+type SectionFormat string
+
+// Supported section formats.
+const (
+	SectionInline SectionFormat = "inline"
+	SectionQuote  SectionFormat = "quote"
+)
+
 // WriteLabels generates a list of entries categorized by label and writes the
 // result to a writer.
 func (j Journal) WriteLabels(w io.Writer, setters ...WriterOption) error {
-	opts := &WriterOptions{
-		Level: 1,
-	}
+	opts := newWriterOptions(setters)
 
-	for _, setter := range setters {
-		setter(opts)
+	excerpts := map[string]string{}
+	for _, e := range j.Entries {
+		excerpts[e.File] = e.Excerpt
 	}
+	sources := map[string][]string{}
 
 	baseHeadingDelim := strings.Repeat("#", opts.Level)
 	for _, label := range j.Labels {
 		fmt.Fprintf(w, "\n%s %s\n", baseHeadingDelim, label.Name)
+		if label.Description != "" {
+			fmt.Fprintf(w, "\n%s\n\n", label.Description)
+		}
 
 		for _, occur := range label.Occurrences {
-			var name string
-
-			location := occur.TagFile
-			if line := occur.Line(); line >= 0 {
-				location = fmt.Sprintf("%s:%d", location, line)
-			}
-			if h, ok := occur.TagFields["heading"]; ok {
-				name = h
-			} else {
+			location := occur.Location()
+			name := occur.Heading
+			if name == "" {
 				name = location
 			}
-			fmt.Fprintf(w, "* [%s](%s)\n", name, location)
+			fmt.Fprintf(w, "* [%s](%s)\n", name, opts.link(location))
+			opts.writeExcerpt(w, excerpts[occur.File])
+			if err := opts.writeSection(w, occur, sources); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// writeSection writes up to Section lines of the section in which a label
+// occurs, nested under a list item. Files are read as needed and their lines
+// cached in sources.
+func (opts WriterOptions) writeSection(w io.Writer, o LabelOccurrence, sources map[string][]string) error {
+	if opts.Section <= 0 || o.Section.Empty() {
+		return nil
+	}
+	start, end := o.Section.Start, o.Section.End
+
+	lines, ok := sources[o.File]
+	if !ok {
+		source, err := ioutil.ReadFile(o.File)
+		if err != nil {
+			return err
+		}
+		lines = strings.Split(strings.ReplaceAll(string(source), "\r\n", "\n"), "\n")
+		sources[o.File] = lines
+	}
+
+	if end > len(lines) {
+		end = len(lines)
+	}
+	if start < 1 || start > end {
+		return nil
+	}
+	section := trimBlankLines(lines[start-1 : end])
+	if len(section) > opts.Section {
+		// Copy the lines, so that appending does not modify the cached source.
+		section = append(append([]string{}, trimBlankLines(section[:opts.Section])...), "…")
+	}
+
+	var prefix string
+	switch opts.SectionFormat {
+	case SectionQuote, "":
+		prefix = "  > "
+	case SectionInline:
+		prefix = "  "
+	default:
+		return fmt.Errorf("unknown section format: %s", opts.SectionFormat)
+	}
+
+	for _, line := range section {
+		fmt.Fprintln(w, strings.TrimRight(prefix+strings.TrimRight(line, " \t"), " "))
+	}
+
+	return nil
+}
+
+// trimBlankLines returns lines without leading and trailing blank lines.
+func trimBlankLines(lines []string) []string {
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// WriteLabelsSplit writes the description and occurrences of each label to a
+// separate file named after the label in dir, along with an index.md file
+// linking to each file.
+func (j Journal) WriteLabelsSplit(dir string, setters ...WriterOption) (err error) {
+	for _, label := range j.Labels {
+		if label.Name == "index" {
+			return fmt.Errorf("label %s would overwrite the index in %s", label.Name, dir)
+		}
+	}
+
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	index, err := os.Create(filepath.Join(dir, "index.md"))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := index.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	for _, label := range j.Labels {
+		file := filepath.Join(dir, label.Name+".md")
+		f, err := os.Create(file)
+		if err != nil {
+			return err
+		}
+
+		labelJournal := Journal{Entries: j.Entries, Labels: []Label{label}}
+		err = labelJournal.WriteLabels(f, append(setters, LinkDir(dir))...)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return err
+		}
+
+		noun := "occurrences"
+		if len(label.Occurrences) == 1 {
+			noun = "occurrence"
+		}
+		_, err = fmt.Fprintf(index, "* [%s](%s) - %d %s\n", label.Name, relpath(dir, file), len(label.Occurrences), noun)
+		if err != nil {
+			return err
 		}
 	}
 
@@ -2,6 +2,9 @@ package journal
 
 import (
 	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -72,3 +75,92 @@ recipe	diary/2006-01-04.md	5;"	kind:label	line:5
 		}
 	}
 }
+
+func TestWriteLabelsSection(t *testing.T) {
+	source := `# Day
+
+## Work :meeting:
+
+Planning with the team.
+Agreed on a date.
+
+Notes to follow.
+
+### Action items
+
+- Book a room
+
+## Home
+`
+
+	file := filepath.Join(t.TempDir(), "2006-01-02.md")
+	if err := ioutil.WriteFile(file, []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tags, err := NewFileParser().Parse(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	j := NewJournal(tags)
+
+	cases := []struct {
+		name     string
+		setters  []WriterOption
+		expected string
+	}{
+		{
+			`quote`,
+			[]WriterOption{SectionLength(4)},
+			`
+# meeting
+* [Work meeting](%[1]s:3)
+  > Planning with the team.
+  > Agreed on a date.
+  >
+  > Notes to follow.
+  > …
+			`,
+		},
+		{
+			`inline`,
+			[]WriterOption{SectionLength(20), SectionStyle(SectionInline)},
+			`
+# meeting
+* [Work meeting](%[1]s:3)
+  Planning with the team.
+  Agreed on a date.
+
+  Notes to follow.
+
+  ### Action items
+
+  - Book a room
+			`,
+		},
+		{
+			`truncated at a blank line`,
+			[]WriterOption{SectionLength(3)},
+			`
+# meeting
+* [Work meeting](%[1]s:3)
+  > Planning with the team.
+  > Agreed on a date.
+  > …
+			`,
+		},
+	}
+
+	for _, tc := range cases {
+		var b bytes.Buffer
+
+		if err := j.WriteLabels(&b, tc.setters...); err != nil {
+			t.Fatal(err)
+		}
+		actual := strings.TrimSpace(b.String())
+		expected := strings.TrimSpace(fmt.Sprintf(tc.expected, file))
+		if actual != expected {
+			t.Errorf("case %s: expected:\n%s\nactual:\n%s", tc.name, expected, actual)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package journal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Scope determines which labels are considered to appear together.
+type Scope string
+
+// Supported co-occurrence scopes.
+const (
+	ScopeEntry   Scope = "entry"
+	ScopeSection Scope = "section"
+)
+
+// LabelStats summarizes the usage of a label.
+type LabelStats struct {
+	Name    string        `json:"name"`
+	Count   int           `json:"count"`
+	Entries int           `json:"entries"`
+	First   time.Time     `json:"first"`
+	Last    time.Time     `json:"last"`
+	Trend   []PeriodCount `json:"trend"`
+}
+
+// PeriodCount is the number of occurrences of a label within the period
+// beginning at Start.
+type PeriodCount struct {
+	Start time.Time `json:"start"`
+	Count int       `json:"count"`
+}
+
+// LabelReport holds usage statistics for every label in a journal, along with
+// the number of entries or sections in which each pair of labels appears
+// together.
+type LabelReport struct {
+	Labels       []LabelStats              `json:"labels"`
+	Scope        Scope                     `json:"scope"`
+	Cooccurrence map[string]map[string]int `json:"cooccurrence"`
+}
+
+// LabelStats computes label usage statistics. Trends count occurrences by
+// day, ISO 8601 week, month, or year.
+func (j Journal) LabelStats(period Grouping, scope Scope) (r LabelReport, err error) {
+	start, err := periodStart(period)
+	if err != nil {
+		return r, err
+	}
+
+	switch scope {
+	case "":
+		scope = ScopeEntry
+	case ScopeEntry, ScopeSection:
+	default:
+		return r, fmt.Errorf("unknown scope: %s", scope)
+	}
+
+	times := map[string]time.Time{}
+	for _, e := range j.Entries {
+		times[e.File] = e.Time
+	}
+
+	// Labels that appear within each entry or section.
+	groups := map[string]map[string]bool{}
+
+	for _, l := range j.Labels {
+		s := LabelStats{Name: l.Name}
+		entries := map[string]bool{}
+		trend := map[time.Time]int{}
+
+		for _, o := range l.Occurrences {
+			t := times[o.File]
+
+			s.Count++
+			if !entries[o.File] {
+				entries[o.File] = true
+				s.Entries++
+			}
+			if s.First.IsZero() || t.Before(s.First) {
+				s.First = t
+			}
+			if t.After(s.Last) {
+				s.Last = t
+			}
+			trend[start(t)]++
+
+			// Sections are identified by their lines, as several sections
+			// of an entry may have the same heading. Tags files without
+			// section lines fall back to the heading.
+			key := o.File
+			if scope == ScopeSection {
+				key = fmt.Sprintf("%s\x00%d-%d", o.File, o.Section.Start, o.Section.End)
+				if o.Section.Empty() {
+					key = o.File + "\x00" + o.Heading
+				}
+			}
+			if groups[key] == nil {
+				groups[key] = map[string]bool{}
+			}
+			groups[key][l.Name] = true
+		}
+
+		for t, n := range trend {
+			s.Trend = append(s.Trend, PeriodCount{Start: t, Count: n})
+		}
+		sort.Slice(s.Trend, func(i, j int) bool {
+			return s.Trend[i].Start.Before(s.Trend[j].Start)
+		})
+
+		r.Labels = append(r.Labels, s)
+	}
+
+	r.Scope = scope
+	r.Cooccurrence = map[string]map[string]int{}
+	for _, labels := range groups {
+		for a := range labels {
+			for b := range labels {
+				if a == b {
+					continue
+				}
+				if r.Cooccurrence[a] == nil {
+					r.Cooccurrence[a] = map[string]int{}
+				}
+				r.Cooccurrence[a][b]++
+			}
+		}
+	}
+
+	return r, nil
+}
+
+// WriteJSON writes the report as JSON.
+func (r LabelReport) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(r)
+}
+
+// WriteLabelStats writes the report as markdown tables.
+func (r LabelReport) WriteLabelStats(w io.Writer, setters ...WriterOption) error {
+	opts := newWriterOptions(setters)
+
+	baseHeadingDelim := strings.Repeat("#", opts.Level)
+	fmt.Fprintf(w, "\n%s Label Usage\n\n", baseHeadingDelim)
+	fmt.Fprintf(w, "| Label | Count | Entries | First | Last |\n")
+	fmt.Fprintf(w, "| --- | --- | --- | --- | --- |\n")
+	for _, s := range r.Labels {
+		fmt.Fprintf(w, "| %s | %d | %d | %s | %s |\n", s.Name, s.Count, s.Entries,
+			s.First.Format(dateFormat), s.Last.Format(dateFormat))
+	}
+
+	fmt.Fprintf(w, "\n%s Label Trends\n\n", baseHeadingDelim)
+	for _, s := range r.Labels {
+		var counts []string
+		for _, p := range s.Trend {
+			counts = append(counts, fmt.Sprintf("%s (%d)", p.Start.Format(dateFormat), p.Count))
+		}
+		fmt.Fprintf(w, "* %s: %s\n", s.Name, strings.Join(counts, ", "))
+	}
+
+	// Only labels that appear with at least one other label are included in
+	// the matrix.
+	var names []string
+	for _, s := range r.Labels {
+		if len(r.Cooccurrence[s.Name]) > 0 {
+			names = append(names, s.Name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	fmt.Fprintf(w, "\n%s Label Co-occurrence by %s\n\n", baseHeadingDelim, r.Scope)
+	fmt.Fprintf(w, "| | %s |\n", strings.Join(names, " | "))
+	fmt.Fprintf(w, "| --- |%s\n", strings.Repeat(" --- |", len(names)))
+	for _, a := range names {
+		cells := make([]string, len(names))
+		for i, b := range names {
+			if n := r.Cooccurrence[a][b]; n > 0 {
+				cells[i] = fmt.Sprintf("%d", n)
+			}
+		}
+		fmt.Fprintf(w, "| **%s** | %s |\n", a, strings.Join(cells, " | "))
+	}
+
+	return nil
+}
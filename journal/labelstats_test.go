@@ -0,0 +1,114 @@
+package journal
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/taylorskalyo/markdown-journal/ctags"
+)
+
+func TestWriteLabelStats(t *testing.T) {
+	format := `
+============= case %s ================
+Expected Output:
+----------
+%v
+Actual Output:
+----------
+%v
+`
+
+	input := `
+recipe	diary/2006-01-03.md	5;"	heading:Lunch	kind:label	line:5
+groceries	diary/2006-01-03.md	9;"	heading:Shopping	kind:label	line:9
+recipe	diary/2007-11-30.md	3;"	heading:Dinner	kind:label	line:3
+groceries	diary/2007-11-30.md	14;"	heading:Dinner	kind:label	line:14
+groceries	diary/2007-11-30.md	16;"	heading:Dinner	kind:label	line:16
+travel	diary/2007-12-01.md	2;"	kind:label	line:2
+`
+
+	cases := []struct {
+		name     string
+		scope    Scope
+		expected string
+	}{
+		{
+			`entry`,
+			ScopeEntry,
+			`
+# Label Usage
+
+| Label | Count | Entries | First | Last |
+| --- | --- | --- | --- | --- |
+| groceries | 3 | 2 | 2006-01-03 | 2007-11-30 |
+| recipe | 2 | 2 | 2006-01-03 | 2007-11-30 |
+| travel | 1 | 1 | 2007-12-01 | 2007-12-01 |
+
+# Label Trends
+
+* groceries: 2006-01-01 (1), 2007-01-01 (2)
+* recipe: 2006-01-01 (1), 2007-01-01 (1)
+* travel: 2007-01-01 (1)
+
+# Label Co-occurrence by entry
+
+| | groceries | recipe |
+| --- | --- | --- |
+| **groceries** |  | 2 |
+| **recipe** | 2 |  |
+			`,
+		},
+		{
+			`section`,
+			ScopeSection,
+			`
+# Label Co-occurrence by section
+
+| | groceries | recipe |
+| --- | --- | --- |
+| **groceries** |  | 1 |
+| **recipe** | 1 |  |
+			`,
+		},
+	}
+
+	for _, tc := range cases {
+		var b bytes.Buffer
+
+		r := ctags.NewReader(strings.NewReader(input))
+		j := NewJournal(r.ReadAll())
+		report, err := j.LabelStats(GroupByYear, tc.scope)
+		if err != nil {
+			t.Fatal(err)
+		}
+		report.WriteLabelStats(&b)
+
+		actual := strings.TrimSpace(b.String())
+		expected := strings.TrimSpace(tc.expected)
+		if !strings.HasSuffix(actual, expected) {
+			t.Errorf(format, tc.name, expected, actual)
+		}
+	}
+}
+
+func TestLabelStatsSectionScope(t *testing.T) {
+	input := `
+a	2006-01-02.md	3;"	heading:Notes	kind:label	line:3	section:2-4
+b	2006-01-02.md	4;"	heading:Notes	kind:label	line:4	section:2-4
+c	2006-01-02.md	7;"	heading:Notes	kind:label	line:7	section:6-7
+`
+
+	j := NewJournal(ctags.NewReader(strings.NewReader(input)).ReadAll())
+	report, err := j.LabelStats(GroupByYear, ScopeSection)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Cooccurrence["a"]["b"] != 1 || report.Cooccurrence["a"]["c"] != 0 {
+		t.Errorf("expected sections with the same heading to be separate, actual %v", report.Cooccurrence)
+	}
+
+	if _, err := (Journal{}).LabelStats(GroupByYear, "paragraph"); err == nil {
+		t.Error("expected an error for an unknown scope")
+	}
+}
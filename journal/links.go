@@ -0,0 +1,160 @@
+package journal
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// linkResolver finds the entries that links point to.
+type linkResolver struct {
+	files map[string]Entry
+	names map[string]Entry
+	dates map[string]Entry
+}
+
+func newLinkResolver(entries []Entry) linkResolver {
+	r := linkResolver{
+		files: map[string]Entry{},
+		names: map[string]Entry{},
+		dates: map[string]Entry{},
+	}
+
+	// Entries are sorted newest first, so iterate in reverse to prefer the
+	// first file of each day when resolving links by date.
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		r.files[filepath.Clean(e.File)] = e
+		r.names[entryName(e.File)] = e
+		if date := e.Time.Format(dateFormat); r.dates[date].File == "" {
+			r.dates[date] = e
+		}
+	}
+
+	return r
+}
+
+// entryName returns the file name of an entry without its extension.
+func entryName(file string) string {
+	return strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+}
+
+func (r linkResolver) resolve(link Link) (Entry, bool) {
+	target := link.Target
+	if link.Style != WikiLink {
+		if !strings.HasSuffix(target, ".md") {
+			return Entry{}, false
+		}
+		e, ok := r.files[filepath.Clean(target)]
+		return e, ok
+	}
+
+	name := entryName(target)
+	if e, ok := r.names[name]; ok {
+		return e, true
+	}
+
+	e, ok := r.dates[name]
+	return e, ok
+}
+
+// Resolve returns the entry that a link points to. Wiki links are resolved by
+// entry file name (e.g. "2024-03-15-retro"), or else by date (e.g.
+// "2024-03-15"). Markdown links are resolved by path.
+func (j Journal) Resolve(link Link) (Entry, bool) {
+	return newLinkResolver(j.Entries).resolve(link)
+}
+
+// Backlinks returns the links pointing to each entry, keyed by the entry's
+// file. Links from an entry to itself are excluded.
+func (j Journal) Backlinks() map[string][]Link {
+	r := newLinkResolver(j.Entries)
+
+	backlinks := map[string][]Link{}
+	for _, link := range j.Links {
+		e, ok := r.resolve(link)
+		if !ok || e.File == link.File {
+			continue
+		}
+		backlinks[e.File] = append(backlinks[e.File], link)
+	}
+
+	// List links from the newest entries first, in the order they appear.
+	for _, links := range backlinks {
+		sort.SliceStable(links, func(i, j int) bool {
+			a, b := links[i], links[j]
+			if a.File != b.File {
+				return a.File > b.File
+			}
+			return a.Line < b.Line
+		})
+	}
+
+	return backlinks
+}
+
+// WriteBacklinks generates a list of the links pointing to each entry and
+// writes the result to a writer. Entries without backlinks are omitted.
+func (j Journal) WriteBacklinks(w io.Writer, setters ...WriterOption) error {
+	opts := newWriterOptions(setters)
+	r := newLinkResolver(j.Entries)
+	backlinks := j.Backlinks()
+
+	baseHeadingDelim := strings.Repeat("#", opts.Level)
+	for _, e := range opts.entries(j.Entries) {
+		links := backlinks[e.File]
+		if len(links) == 0 {
+			continue
+		}
+
+		title := e.Title()
+		if title == "" {
+			title = e.File
+		}
+		fmt.Fprintf(w, "\n%s [%s](%s)\n", baseHeadingDelim, title, opts.link(e.File))
+		opts.writeLinks(w, r, links)
+	}
+
+	return nil
+}
+
+// WriteReferencedBy writes a "Referenced by" section listing the links that
+// point to the given entry file. Nothing is written if there are none.
+func (j Journal) WriteReferencedBy(w io.Writer, file string, setters ...WriterOption) error {
+	opts := newWriterOptions(setters)
+	r := newLinkResolver(j.Entries)
+
+	e, ok := r.files[filepath.Clean(file)]
+	if !ok {
+		return fmt.Errorf("%s: %w", file, errNotEntry)
+	}
+
+	links := j.Backlinks()[e.File]
+	if len(links) == 0 {
+		return nil
+	}
+
+	fmt.Fprintf(w, "\n%s Referenced by\n", strings.Repeat("#", opts.Level))
+	opts.writeLinks(w, r, links)
+
+	return nil
+}
+
+// writeLinks lists links by the heading they appear under, or else the title
+// of the entry they appear in.
+func (opts WriterOptions) writeLinks(w io.Writer, r linkResolver, links []Link) {
+	for _, link := range links {
+		location := link.Location()
+
+		name := link.Heading
+		if name == "" {
+			name = r.files[filepath.Clean(link.File)].Title()
+		}
+		if name == "" {
+			name = location
+		}
+		fmt.Fprintf(w, "* [%s](%s)\n", name, opts.link(location))
+	}
+}
@@ -0,0 +1,80 @@
+package journal
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/taylorskalyo/markdown-journal/ctags"
+)
+
+func TestWriteBacklinks(t *testing.T) {
+	format := `
+============= case %s ================
+Expected Output:
+----------
+%v
+Actual Output:
+----------
+%v
+`
+
+	input := `
+Retro	diary/2006-01-03-retro.md	1;"	kind:title	line:1
+Plan	diary/2006-01-03.md	1;"	kind:title	line:1
+2006-01-03-retro	diary/2006-01-04.md	2;"	heading:Notes	kind:link	line:2	style:wiki	target:2006-01-03-retro
+2006-01-03	diary/2006-01-04.md	5;"	kind:link	line:5	style:wiki	target:2006-01-03
+2006-01-04	diary/2006-01-04.md	6;"	kind:link	line:6	style:wiki	target:2006-01-04
+missing	diary/2006-01-04.md	7;"	kind:link	line:7	style:wiki	target:missing
+2006-01-03.md	diary/2006-01-05.md	3;"	kind:link	line:3	style:markdown	target:diary/2006-01-03.md
+`
+
+	cases := []struct {
+		name     string
+		write    func(j Journal, w *bytes.Buffer) error
+		expected string
+	}{
+		{
+			`all entries`,
+			func(j Journal, w *bytes.Buffer) error { return j.WriteBacklinks(w) },
+			`
+# [Plan](diary/2006-01-03.md)
+* [diary/2006-01-05.md:3](diary/2006-01-05.md:3)
+* [diary/2006-01-04.md:5](diary/2006-01-04.md:5)
+
+# [Retro](diary/2006-01-03-retro.md)
+* [Notes](diary/2006-01-04.md:2)
+			`,
+		},
+		{
+			`referenced by`,
+			func(j Journal, w *bytes.Buffer) error {
+				return j.WriteReferencedBy(w, "diary/2006-01-03-retro.md", HeadingLevel(2))
+			},
+			`
+## Referenced by
+* [Notes](diary/2006-01-04.md:2)
+			`,
+		},
+		{
+			`not referenced`,
+			func(j Journal, w *bytes.Buffer) error { return j.WriteReferencedBy(w, "diary/2006-01-05.md") },
+			``,
+		},
+	}
+
+	for _, tc := range cases {
+		var b bytes.Buffer
+
+		r := ctags.NewReader(strings.NewReader(input))
+		j := NewJournal(r.ReadAll())
+		if err := tc.write(j, &b); err != nil {
+			t.Errorf("case %s: %v", tc.name, err)
+		}
+		actual := strings.TrimSpace(b.String())
+		expected := strings.TrimSpace(tc.expected)
+		if actual != expected {
+			t.Errorf(format, tc.name, expected, actual)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package journal
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+const (
+	highlightStart = "\x1b[7m"
+	highlightEnd   = "\x1b[0m"
+)
+
+// listField replaces characters that would break a line of the list.
+var listField = strings.NewReplacer("\t", " ", "\r", " ", "\n", " ")
+
+// WriteList writes one line per entry, heading, and label occurrence, for use
+// with fuzzy finders such as fzf. Each line has four tab-separated fields: the
+// location ("path:line"), the entry's date, a title, and the labels that occur
+// there.
+func (j Journal) WriteList(w io.Writer, setters ...WriterOption) error {
+	opts := newWriterOptions(setters)
+
+	for _, e := range opts.entries(j.Entries) {
+		date := e.Time.Format(dateFormat)
+		row := func(loc, title string, labels []string) error {
+			for i, label := range labels {
+				labels[i] = ":" + label + ":"
+			}
+			_, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+				listField.Replace(loc), date, listField.Replace(title), strings.Join(labels, " "))
+			return err
+		}
+
+		line := 1
+		if len(e.Headings) > 0 {
+			line = e.Headings[0].Line
+		}
+		if err := row(location(e.File, line), e.Title(), e.Labels()); err != nil {
+			return err
+		}
+
+		var err error
+		e.Walk(func(n Node) bool {
+			switch {
+			case n.Heading != nil && n.Heading.Line != line:
+				err = row(location(e.File, n.Heading.Line), n.Heading.Text, e.headingLabels(*n.Heading))
+			case n.Label != nil:
+				title := n.Label.Heading
+				if title == "" {
+					title = e.Title()
+				}
+				err = row(n.Label.Location(), title, []string{n.Label.label()})
+			}
+			return err == nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// headingLabels returns the labels that occur between a heading and the next
+// heading of any level, in the order they first appear.
+func (e Entry) headingLabels(h Heading) (labels []string) {
+	end := 0
+	for _, other := range e.Headings {
+		if other.Line > h.Line {
+			end = other.Line
+			break
+		}
+	}
+
+	seen := map[string]bool{}
+	for _, o := range e.Occurrences {
+		if o.Line < h.Line || end > 0 && o.Line >= end {
+			continue
+		}
+		if name := o.label(); !seen[name] {
+			seen[name] = true
+			labels = append(labels, name)
+		}
+	}
+
+	return labels
+}
+
+// WritePreview writes the section of the entry that contains the given line,
+// starting with its heading, and highlights the line. If line is less than 1,
+// the whole entry is written.
+func (e Entry) WritePreview(w io.Writer, line int, setters ...WriterOption) error {
+	opts := newWriterOptions(setters)
+
+	source, err := ioutil.ReadFile(e.File)
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(strings.ReplaceAll(string(source), "\r\n", "\n"), "\n")
+	if line > len(lines) {
+		return fmt.Errorf("%s: line %d is past the end of the file", e.File, line)
+	}
+
+	r := e.previewRange(line, len(lines))
+	for r.End > r.Start && strings.TrimSpace(lines[r.End-1]) == "" {
+		r.End--
+	}
+
+	for i := r.Start; i <= r.End; i++ {
+		text := lines[i-1]
+		switch {
+		case i == line && opts.Highlight:
+			_, err = fmt.Fprintf(w, "%s%s%s\n", highlightStart, text, highlightEnd)
+		case opts.Highlight:
+			_, err = fmt.Fprintln(w, text)
+		case i == line:
+			_, err = fmt.Fprintf(w, "> %s\n", text)
+		default:
+			_, err = fmt.Fprintf(w, "  %s\n", text)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// previewRange returns the lines of the section that contains the given line:
+// from the nearest heading at or before the line to the line before the next
+// heading of equal or higher level.
+func (e Entry) previewRange(line, last int) LineRange {
+	r := LineRange{1, last}
+	if line < 1 {
+		return r
+	}
+
+	var heading *Heading
+	for i, h := range e.Headings {
+		if h.Line > line {
+			break
+		}
+		heading = &e.Headings[i]
+	}
+
+	for _, h := range e.Headings {
+		if h.Line <= line {
+			continue
+		}
+		if heading == nil || h.Level <= heading.Level {
+			r.End = h.Line - 1
+			break
+		}
+	}
+	if heading != nil {
+		r.Start = heading.Line
+	}
+
+	return r
+}
@@ -0,0 +1,96 @@
+package journal
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+const testListEntry = `# Day :work:
+
+Intro :a:
+
+## Lunch
+
+Ate :food:
+
+## Evening
+
+TV
+`
+
+func writeTestEntries(t *testing.T, files map[string]string) (entries []Entry) {
+	t.Helper()
+
+	dir := t.TempDir()
+	p := NewFileParser()
+	for name, content := range files {
+		file := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		e, err := p.ParseEntry(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, e)
+	}
+
+	return entries
+}
+
+func TestWriteList(t *testing.T) {
+	entries := writeTestEntries(t, map[string]string{
+		"2024-01-02.md":      testListEntry,
+		"2024-01-03-trip.md": "# Road\ttrip\n\n:misc:\n",
+	})
+	j := FromEntries(entries)
+
+	var b bytes.Buffer
+	if err := j.WriteList(&b); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := filepath.Dir(entries[0].File) + string(filepath.Separator)
+	expected := dir + "2024-01-03-trip.md:1\t2024-01-03\tRoad trip\t:misc:\n" +
+		dir + "2024-01-03-trip.md:3\t2024-01-03\tRoad trip\t:misc:\n" +
+		dir + "2024-01-02.md:1\t2024-01-02\tDay work\t:work: :a: :food:\n" +
+		dir + "2024-01-02.md:1\t2024-01-02\tDay work\t:work:\n" +
+		dir + "2024-01-02.md:3\t2024-01-02\tDay work\t:a:\n" +
+		dir + "2024-01-02.md:5\t2024-01-02\tLunch\t:food:\n" +
+		dir + "2024-01-02.md:7\t2024-01-02\tLunch\t:food:\n" +
+		dir + "2024-01-02.md:9\t2024-01-02\tEvening\t\n"
+	if actual := b.String(); actual != expected {
+		t.Errorf("expected:\n%s\nactual:\n%s", expected, actual)
+	}
+}
+
+func TestWritePreview(t *testing.T) {
+	e := writeTestEntries(t, map[string]string{"2024-01-02.md": testListEntry})[0]
+
+	cases := []struct {
+		line      int
+		highlight bool
+		expected  string
+	}{
+		{7, false, "  ## Lunch\n  \n> Ate :food:\n"},
+		{5, true, "\x1b[7m## Lunch\x1b[0m\n\nAte :food:\n"},
+		{3, false, "  # Day :work:\n  \n> Intro :a:\n  \n  ## Lunch\n  \n  Ate :food:\n  \n  ## Evening\n  \n  TV\n"},
+		{0, true, testListEntry},
+	}
+
+	for _, tc := range cases {
+		var b bytes.Buffer
+		if err := e.WritePreview(&b, tc.line, HighlightLine(tc.highlight)); err != nil {
+			t.Fatal(err)
+		}
+		if actual := b.String(); actual != tc.expected {
+			t.Errorf("line %d: expected %q, actual %q", tc.line, tc.expected, actual)
+		}
+	}
+
+	if err := e.WritePreview(&bytes.Buffer{}, 100); err == nil {
+		t.Error("expected an error for a line past the end of the file")
+	}
+}
@@ -0,0 +1,178 @@
+package journal
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"time"
+
+	"github.com/taylorskalyo/markdown-journal/ctags"
+)
+
+// LoadOptions stores options for Load.
+type LoadOptions struct {
+	// Recurse determines whether subdirectories of the roots are searched for
+	// entries.
+	Recurse bool
+
+	// TagFile is a ctags file to read entry metadata from instead of parsing
+	// the entries. If Tags is not nil, it is read instead.
+	TagFile string
+	Tags    io.Reader
+
+	// Jobs is the maximum number of entries parsed concurrently. If less than
+	// 1, GOMAXPROCS is used.
+	Jobs int
+
+	// Since and Until limit the journal to entries dated within the range,
+	// inclusive. A zero time leaves that end of the range unbounded.
+	Since time.Time
+	Until time.Time
+
+	// Labels, if not empty, limits the journal's labels to those named.
+	Labels []string
+
+	// Journal options used to build the journal.
+	Journal []JournalOption
+}
+
+// LoadOption applies an option to a LoadOptions struct.
+type LoadOption func(*LoadOptions)
+
+// Load finds the journal entries in the given roots, or the current directory
+// if none are given, and builds a journal from them.
+//
+// Entries that cannot be loaded are skipped. The journal built from the rest
+// is returned along with a Diagnostics error describing each one; the same
+// diagnostics are stored in the journal. Any other error, including the
+// context's error if it is done before loading finishes, is fatal.
+func Load(ctx context.Context, roots []string, setters ...LoadOption) (j Journal, err error) {
+	opts := &LoadOptions{}
+	for _, setter := range setters {
+		setter(opts)
+	}
+
+	if len(roots) == 0 {
+		roots = []string{"."}
+	}
+
+	var diagnostics Diagnostics
+	collect := func(err error) error {
+		var d Diagnostics
+		if errors.As(err, &d) {
+			diagnostics = append(diagnostics, d...)
+			return nil
+		}
+		return err
+	}
+
+	paths, err := files(ctx, roots, opts.Recurse)
+	if err = collect(err); err != nil {
+		return j, err
+	}
+
+	entries, err := opts.entries(ctx, paths)
+	if err = collect(err); err != nil {
+		return j, err
+	}
+
+	j = FromEntries(entries, opts.Journal...)
+	if !opts.Since.IsZero() || !opts.Until.IsZero() {
+		j = j.Between(opts.Since, opts.Until)
+	}
+	if len(opts.Labels) > 0 {
+		j = j.FilterLabels(opts.Labels)
+	}
+
+	j.Diagnostics = diagnostics
+	if len(j.Diagnostics) > 0 {
+		return j, j.Diagnostics
+	}
+
+	return j, nil
+}
+
+// entries returns the given entries, read from a tag file or else parsed.
+// Entries that cannot be loaded are described by a Diagnostics error.
+func (opts LoadOptions) entries(ctx context.Context, paths []string) ([]Entry, error) {
+	r := opts.Tags
+	if r == nil && opts.TagFile != "" {
+		f, err := os.Open(opts.TagFile)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+	if r == nil {
+		return parseEntries(ctx, paths, opts.Jobs)
+	}
+
+	// Some files may not have any labels or headings and therefore no ctags
+	// entries. Ensure every file has at least one ctags entry.
+	tags := ctags.NewReader(r).ReadAll()
+	for _, file := range paths {
+		tags = append(tags, ctags.TagLine{
+			TagFile:   file,
+			TagFields: ctags.TagFields{"line": "0"},
+		})
+	}
+
+	entries, diagnostics := entriesFromTags(tags)
+	if len(diagnostics) > 0 {
+		return entries, diagnostics
+	}
+
+	return entries, nil
+}
+
+// Recurse sets the Recurse LoadOption value.
+func Recurse(recurse bool) LoadOption {
+	return func(opts *LoadOptions) {
+		opts.Recurse = recurse
+	}
+}
+
+// TagFile sets the TagFile LoadOption value.
+func TagFile(name string) LoadOption {
+	return func(opts *LoadOptions) {
+		opts.TagFile = name
+	}
+}
+
+// TagReader sets the Tags LoadOption value.
+func TagReader(r io.Reader) LoadOption {
+	return func(opts *LoadOptions) {
+		opts.Tags = r
+	}
+}
+
+// Jobs sets the Jobs LoadOption value.
+func Jobs(n int) LoadOption {
+	return func(opts *LoadOptions) {
+		opts.Jobs = n
+	}
+}
+
+// DateRange sets the Since and Until LoadOption values.
+func DateRange(since, until time.Time) LoadOption {
+	return func(opts *LoadOptions) {
+		opts.Since = since
+		opts.Until = until
+	}
+}
+
+// OnlyLabels sets the Labels LoadOption value.
+func OnlyLabels(names ...string) LoadOption {
+	return func(opts *LoadOptions) {
+		opts.Labels = names
+	}
+}
+
+// BuildOptions appends to the Journal LoadOption value.
+func BuildOptions(setters ...JournalOption) LoadOption {
+	return func(opts *LoadOptions) {
+		opts.Journal = append(opts.Journal, setters...)
+	}
+}
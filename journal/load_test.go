@@ -0,0 +1,112 @@
+package journal
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	entries := map[string]string{
+		"2006-01-02.md":        "# Monday\n\n:work:\n",
+		"2006-01-03.md":        "# Tuesday\n\n:Work: :home:\n",
+		"2006-02-30.md":        "# Invalid\n",
+		"notes.md":             ":work:\n",
+		"sub/2006-01-04.md":    "# Wednesday\n\n:work:\n",
+		"sub/2006-01-05-no.md": "No heading.\n",
+	}
+	for name, source := range entries {
+		file := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(file, []byte(source), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	date := func(s string) time.Time {
+		d, _ := time.Parse(dateFormat, s)
+		return d
+	}
+
+	cases := []struct {
+		name    string
+		setters []LoadOption
+		entries []string
+		labels  []string
+	}{
+		{
+			`defaults`,
+			nil,
+			[]string{"2006-01-03.md", "2006-01-02.md"},
+			[]string{"Work", "home", "work"},
+		},
+		{
+			`recurse`,
+			[]LoadOption{Recurse(true), Jobs(2)},
+			[]string{"sub/2006-01-05-no.md", "sub/2006-01-04.md", "2006-01-03.md", "2006-01-02.md"},
+			[]string{"Work", "home", "work"},
+		},
+		{
+			`filters`,
+			[]LoadOption{
+				Recurse(true),
+				DateRange(date("2006-01-03"), date("2006-01-04")),
+				OnlyLabels("WORK"),
+				BuildOptions(LabelNormalization(Normalization{FoldCase: true})),
+			},
+			[]string{"sub/2006-01-04.md", "2006-01-03.md"},
+			[]string{"work"},
+		},
+		{
+			`tag reader`,
+			[]LoadOption{TagReader(strings.NewReader(
+				"work\t" + filepath.Join(dir, "2006-01-02.md") + "\t1;\"\tkind:label\tline:1\n",
+			))},
+			[]string{"2006-01-03.md", "2006-01-02.md"},
+			[]string{"work"},
+		},
+	}
+
+	for _, tc := range cases {
+		j, err := Load(context.Background(), []string{dir}, tc.setters...)
+
+		var diagnostics Diagnostics
+		if !errors.As(err, &diagnostics) || len(diagnostics) != 1 || !strings.HasSuffix(diagnostics[0].File, "2006-02-30.md") {
+			t.Errorf("case %s: expected diagnostic for invalid entry, got %v", tc.name, err)
+		}
+
+		var actual []string
+		for _, e := range j.Entries {
+			rel, _ := filepath.Rel(dir, e.File)
+			actual = append(actual, filepath.ToSlash(rel))
+		}
+		if strings.Join(actual, ",") != strings.Join(tc.entries, ",") {
+			t.Errorf("case %s: expected entries %v, actual %v", tc.name, tc.entries, actual)
+		}
+
+		actual = nil
+		for _, l := range j.Labels {
+			actual = append(actual, l.Name)
+		}
+		if strings.Join(actual, ",") != strings.Join(tc.labels, ",") {
+			t.Errorf("case %s: expected labels %v, actual %v", tc.name, tc.labels, actual)
+		}
+	}
+}
+
+func TestLoadCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := Load(ctx, []string{t.TempDir()}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,128 @@
+package journal
+
+import (
+	"strings"
+	"time"
+)
+
+// Locale holds the month and weekday names used when formatting dates. Days
+// are indexed by time.Weekday, so they begin with Sunday.
+type Locale struct {
+	Months      [12]string
+	ShortMonths [12]string
+	Days        [7]string
+	ShortDays   [7]string
+	Week        string
+}
+
+// DefaultLocale is the locale used when none is specified.
+const DefaultLocale = "en"
+
+var locales = map[string]Locale{
+	"en": {
+		Months:      [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		ShortMonths: [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+		Days:        [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+		ShortDays:   [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+		Week:        "Week",
+	},
+	"de": {
+		Months:      [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		ShortMonths: [12]string{"Jan", "Feb", "Mär", "Apr", "Mai", "Jun", "Jul", "Aug", "Sep", "Okt", "Nov", "Dez"},
+		Days:        [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+		ShortDays:   [7]string{"So", "Mo", "Di", "Mi", "Do", "Fr", "Sa"},
+		Week:        "Woche",
+	},
+	"es": {
+		Months:      [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		ShortMonths: [12]string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sep", "oct", "nov", "dic"},
+		Days:        [7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+		ShortDays:   [7]string{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"},
+		Week:        "Semana",
+	},
+	"fr": {
+		Months:      [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		ShortMonths: [12]string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+		Days:        [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+		ShortDays:   [7]string{"dim.", "lun.", "mar.", "mer.", "jeu.", "ven.", "sam."},
+		Week:        "Semaine",
+	},
+	"it": {
+		Months:      [12]string{"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno", "luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"},
+		ShortMonths: [12]string{"gen", "feb", "mar", "apr", "mag", "giu", "lug", "ago", "set", "ott", "nov", "dic"},
+		Days:        [7]string{"domenica", "lunedì", "martedì", "mercoledì", "giovedì", "venerdì", "sabato"},
+		ShortDays:   [7]string{"dom", "lun", "mar", "mer", "gio", "ven", "sab"},
+		Week:        "Settimana",
+	},
+	"nl": {
+		Months:      [12]string{"januari", "februari", "maart", "april", "mei", "juni", "juli", "augustus", "september", "oktober", "november", "december"},
+		ShortMonths: [12]string{"jan", "feb", "mrt", "apr", "mei", "jun", "jul", "aug", "sep", "okt", "nov", "dec"},
+		Days:        [7]string{"zondag", "maandag", "dinsdag", "woensdag", "donderdag", "vrijdag", "zaterdag"},
+		ShortDays:   [7]string{"zo", "ma", "di", "wo", "do", "vr", "za"},
+		Week:        "Week",
+	},
+	"pt": {
+		Months:      [12]string{"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"},
+		ShortMonths: [12]string{"jan", "fev", "mar", "abr", "mai", "jun", "jul", "ago", "set", "out", "nov", "dez"},
+		Days:        [7]string{"domingo", "segunda-feira", "terça-feira", "quarta-feira", "quinta-feira", "sexta-feira", "sábado"},
+		ShortDays:   [7]string{"dom", "seg", "ter", "qua", "qui", "sex", "sáb"},
+		Week:        "Semana",
+	},
+	"sv": {
+		Months:      [12]string{"januari", "februari", "mars", "april", "maj", "juni", "juli", "augusti", "september", "oktober", "november", "december"},
+		ShortMonths: [12]string{"jan", "feb", "mar", "apr", "maj", "jun", "jul", "aug", "sep", "okt", "nov", "dec"},
+		Days:        [7]string{"söndag", "måndag", "tisdag", "onsdag", "torsdag", "fredag", "lördag"},
+		ShortDays:   [7]string{"sön", "mån", "tis", "ons", "tor", "fre", "lör"},
+		Week:        "Vecka",
+	},
+}
+
+// LookupLocale returns the bundled locale with the given name. Names may
+// include a territory and encoding (e.g. "de_DE.UTF-8"); only the language
+// portion is used.
+func LookupLocale(name string) (Locale, bool) {
+	if i := strings.IndexAny(name, "_-."); i >= 0 {
+		name = name[:i]
+	}
+	l, ok := locales[strings.ToLower(name)]
+
+	return l, ok
+}
+
+// Format returns a textual representation of t like time.Time.Format, but
+// month and weekday names are taken from the locale.
+func (l Locale) Format(t time.Time, layout string) string {
+	var b strings.Builder
+
+	names := []struct {
+		token string
+		value string
+	}{
+		// Longer tokens must precede their prefixes.
+		{"January", l.Months[t.Month()-1]},
+		{"Jan", l.ShortMonths[t.Month()-1]},
+		{"Monday", l.Days[t.Weekday()]},
+		{"Mon", l.ShortDays[t.Weekday()]},
+	}
+
+	start := 0
+	for i := 0; i < len(layout); {
+		matched := false
+		for _, n := range names {
+			if strings.HasPrefix(layout[i:], n.token) {
+				b.WriteString(t.Format(layout[start:i]))
+				b.WriteString(n.value)
+				i += len(n.token)
+				start = i
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			i++
+		}
+	}
+	b.WriteString(t.Format(layout[start:]))
+
+	return b.String()
+}
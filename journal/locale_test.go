@@ -0,0 +1,39 @@
+package journal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLocaleFormat(t *testing.T) {
+	date := time.Date(2024, time.March, 15, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		locale   string
+		layout   string
+		expected string
+	}{
+		{`en`, `02 Mon`, `15 Fri`},
+		{`en`, `Monday, January 2, 2006`, `Friday, March 15, 2024`},
+		{`de_DE.UTF-8`, `Monday, 2. January 2006`, `Freitag, 15. März 2024`},
+		{`fr`, `Mon 2 Jan`, `ven. 15 mars`},
+		{`es`, `January`, `marzo`},
+		{`sv`, `2006-01-02 Mon`, `2024-03-15 fre`},
+	}
+
+	for _, tc := range cases {
+		l, ok := LookupLocale(tc.locale)
+		if !ok {
+			t.Errorf("locale %q not found", tc.locale)
+			continue
+		}
+
+		if actual := l.Format(date, tc.layout); actual != tc.expected {
+			t.Errorf("%s: Format(%q) = %q, expected %q", tc.locale, tc.layout, actual, tc.expected)
+		}
+	}
+
+	if _, ok := LookupLocale("xx"); ok {
+		t.Errorf("unexpected locale xx")
+	}
+}
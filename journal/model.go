@@ -0,0 +1,122 @@
+package journal
+
+import "fmt"
+
+// Heading is a heading within a journal entry.
+type Heading struct {
+	Text  string
+	Level int
+	Line  int
+}
+
+// LineRange is a range of lines, inclusive.
+type LineRange struct {
+	Start int
+	End   int
+}
+
+// Empty reports whether the range contains no lines.
+func (r LineRange) Empty() bool {
+	return r.Start < 1 || r.Start > r.End
+}
+
+// Section is a heading and its content. The section preceding an entry's first
+// heading has no heading.
+type Section struct {
+	Heading Heading
+	Lines   LineRange
+}
+
+// Node is a heading or label occurrence within an entry. Exactly one of
+// Heading and Label is set.
+type Node struct {
+	Heading *Heading
+	Label   *LabelOccurrence
+}
+
+// LabelOccurrence is an occurrence of a label within a journal entry.
+type LabelOccurrence struct {
+	// Name is the label as written. Label is the display name of the label it
+	// belongs to, set when the journal is built.
+	Name  string
+	Label string
+
+	File string
+	Line int
+
+	// Heading is the text of the heading the label appears under, if any.
+	// Section is the content of that heading's section, or of the content
+	// preceding the first heading.
+	Heading string
+	Section LineRange
+
+	// key identifies the label the occurrence belongs to.
+	key string
+}
+
+// Location returns a "file:line" reference to the label occurrence.
+func (o LabelOccurrence) Location() string {
+	return location(o.File, o.Line)
+}
+
+// Task is a task list item within a journal entry, e.g. "- [ ] Call Bob".
+type Task struct {
+	Text string
+	Done bool
+
+	File    string
+	Line    int
+	Heading string
+}
+
+// Location returns a "file:line" reference to the task.
+func (t Task) Location() string {
+	return location(t.File, t.Line)
+}
+
+// LinkStyle is the syntax of a link.
+type LinkStyle string
+
+// Supported link styles.
+const (
+	MarkdownLink LinkStyle = "markdown"
+	WikiLink     LinkStyle = "wiki"
+)
+
+// Link is a link from a journal entry to another file. Destination is the link
+// as written. For markdown links and images, Target is the linked file's path;
+// for wiki links, it is the name of the linked entry.
+type Link struct {
+	Destination string
+	Target      string
+	Style       LinkStyle
+
+	File    string
+	Line    int
+	Heading string
+}
+
+// Location returns a "file:line" reference to the link.
+func (l Link) Location() string {
+	return location(l.File, l.Line)
+}
+
+// label returns the canonical name of the label, or its name as written if the
+// occurrence is not part of a journal.
+func (o LabelOccurrence) label() string {
+	if o.Label != "" {
+		return o.Label
+	}
+
+	return o.Name
+}
+
+// location returns a "file:line" reference. The line is omitted if it is
+// unknown.
+func location(file string, line int) string {
+	if line > 0 {
+		return fmt.Sprintf("%s:%d", file, line)
+	}
+
+	return file
+}
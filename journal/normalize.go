@@ -0,0 +1,70 @@
+package journal
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Normalization determines which label spellings are treated as the same
+// label.
+type Normalization struct {
+	// FoldCase treats labels that differ only in case as equal, e.g. "Work"
+	// and "work", or "Straße" and "STRASSE".
+	FoldCase bool
+
+	// Unicode treats canonically equivalent labels as equal, e.g. "é" written
+	// as a single character or as "e" followed by a combining accent.
+	Unicode bool
+
+	// Separators treats dashes (`-`) and underscores (`_`) as equal.
+	Separators bool
+}
+
+// Supported normalization names.
+const (
+	NormalizeCase       = "case"
+	NormalizeUnicode    = "unicode"
+	NormalizeSeparators = "separators"
+	NormalizeAll        = "all"
+	NormalizeNone       = "none"
+)
+
+// ParseNormalization returns the Normalization enabling each of the named
+// normalizations.
+func ParseNormalization(names []string) (n Normalization, err error) {
+	for _, name := range names {
+		switch strings.TrimSpace(name) {
+		case NormalizeCase:
+			n.FoldCase = true
+		case NormalizeUnicode:
+			n.Unicode = true
+		case NormalizeSeparators:
+			n.Separators = true
+		case NormalizeAll:
+			n = Normalization{FoldCase: true, Unicode: true, Separators: true}
+		case NormalizeNone, "":
+		default:
+			return n, fmt.Errorf("unknown label normalization: %s", name)
+		}
+	}
+
+	return n, nil
+}
+
+// Key returns the form of a label name used to compare it with other labels.
+func (n Normalization) Key(name string) string {
+	if n.Unicode {
+		name = norm.NFC.String(name)
+	}
+	if n.FoldCase {
+		name = cases.Fold().String(name)
+	}
+	if n.Separators {
+		name = strings.ReplaceAll(name, "_", "-")
+	}
+
+	return name
+}
@@ -0,0 +1,105 @@
+package journal
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/taylorskalyo/markdown-journal/ctags"
+)
+
+func TestNormalizationKey(t *testing.T) {
+	all := Normalization{FoldCase: true, Unicode: true, Separators: true}
+
+	testCases := []struct {
+		n        Normalization
+		name     string
+		expected string
+	}{
+		{Normalization{}, "Meal_Prep", "Meal_Prep"},
+		{Normalization{FoldCase: true}, "Meal_Prep", "meal_prep"},
+		{Normalization{Separators: true}, "Meal_Prep", "Meal-Prep"},
+		{Normalization{Unicode: true}, "caf\u00e9", "caf\u00e9"},
+		{Normalization{Unicode: true}, "cafe\u0301", "caf\u00e9"},
+		{Normalization{Unicode: true}, "a\u0323\u0302", "\u1ead"},
+		{all, "Cafe\u0301_Visit", "caf\u00e9-visit"},
+		{all, "\u0395\u0301", "\u03ad"},
+		{Normalization{FoldCase: true}, "Stra\u00dfe", "strasse"},
+	}
+
+	for _, tc := range testCases {
+		if actual := tc.n.Key(tc.name); actual != tc.expected {
+			t.Errorf("%+v %q: expected %q, actual %q", tc.n, tc.name, tc.expected, actual)
+		}
+	}
+}
+
+func TestNormalizationKeyEquivalent(t *testing.T) {
+	n := Normalization{FoldCase: true, Unicode: true}
+
+	testCases := [][2]string{
+		{"a\u0302\u0323", "a\u0323\u0302"},
+		{"\u00e9\u0323", "e\u0323\u0301"},
+		{"\uac00", "\u1100\u1161"},
+		{"Stra\u00dfe", "STRASSE"},
+	}
+
+	for _, tc := range testCases {
+		if a, b := n.Key(tc[0]), n.Key(tc[1]); a != b {
+			t.Errorf("expected %q and %q to be equal, actual %q and %q", tc[0], tc[1], a, b)
+		}
+	}
+}
+
+func TestParseNormalization(t *testing.T) {
+	n, err := ParseNormalization([]string{"case", "separators"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != (Normalization{FoldCase: true, Separators: true}) {
+		t.Errorf("unexpected normalization: %+v", n)
+	}
+
+	if _, err := ParseNormalization([]string{"accents"}); err == nil {
+		t.Error("expected error for unknown normalization")
+	}
+}
+
+func TestNormalizedLabels(t *testing.T) {
+	input := "\n" +
+		"Work\tdiary/2006-01-02.md\t2;\"\tkind:label\tline:2\n" +
+		"work\tdiary/2006-01-03.md\t2;\"\tkind:label\tline:2\n" +
+		"work\tdiary/2006-01-04.md\t2;\"\tkind:label\tline:2\n" +
+		"cafe\u0301\tdiary/2006-01-04.md\t3;\"\tkind:label\tline:3\n" +
+		"caf\u00e9\tdiary/2006-01-05.md\t2;\"\tkind:label\tline:2\n" +
+		"caf\u00e9\tdiary/2006-01-06.md\t2;\"\tkind:label\tline:2\n" +
+		"meal_prep\tdiary/2006-01-05.md\t3;\"\tkind:label\tline:3\n"
+
+	expected := "\n# caf\u00e9\n" + `* [diary/2006-01-06.md:2](diary/2006-01-06.md:2)
+* [diary/2006-01-05.md:2](diary/2006-01-05.md:2)
+* [diary/2006-01-04.md:3](diary/2006-01-04.md:3)
+
+# work
+* [diary/2006-01-04.md:2](diary/2006-01-04.md:2)
+* [diary/2006-01-03.md:2](diary/2006-01-03.md:2)
+* [diary/2006-01-02.md:2](diary/2006-01-02.md:2)
+`
+
+	r := ctags.NewReader(strings.NewReader(input))
+	j := NewJournal(r.ReadAll(), LabelNormalization(Normalization{FoldCase: true, Unicode: true, Separators: true}))
+	j = j.FilterLabels([]string{"WORK", "CAFE\u0301"})
+
+	var b bytes.Buffer
+	if err := j.WriteLabels(&b); err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != expected {
+		t.Errorf("expected:\n%s\nactual:\n%s", expected, b.String())
+	}
+
+	for _, e := range j.Entries {
+		if e.File == "diary/2006-01-02.md" && strings.Join(e.Labels(), ",") != "work" {
+			t.Errorf("expected entry label to use display name, actual %v", e.Labels())
+		}
+	}
+}
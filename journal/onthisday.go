@@ -0,0 +1,139 @@
+package journal
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var reWindow = regexp.MustCompile(`^(\d+)([dwmy])$`)
+
+// Window is a span of time before a date, such as "one week ago".
+type Window struct {
+	Years, Months, Days int
+}
+
+// ParseWindow parses a window given as a number followed by a unit: d (days),
+// w (weeks), m (months), or y (years). For example, "2w" is two weeks.
+func ParseWindow(s string) (Window, error) {
+	matches := reWindow.FindStringSubmatch(s)
+	if len(matches) == 0 {
+		return Window{}, fmt.Errorf("invalid window: %s", s)
+	}
+
+	n, _ := strconv.Atoi(matches[1])
+	switch matches[2] {
+	case "d":
+		return Window{Days: n}, nil
+	case "w":
+		return Window{Days: 7 * n}, nil
+	case "m":
+		return Window{Months: n}, nil
+	}
+
+	return Window{Years: n}, nil
+}
+
+// Before returns the date the window ends before t.
+func (win Window) Before(t time.Time) time.Time {
+	return t.AddDate(-win.Years, -win.Months, -win.Days)
+}
+
+// String returns a description of the window, e.g. "2 weeks ago", or "today"
+// for an empty window.
+func (win Window) String() string {
+	n, unit := win.Days, "day"
+	switch {
+	case win.Years > 0:
+		n, unit = win.Years, "year"
+	case win.Months > 0:
+		n, unit = win.Months, "month"
+	case win.Days == 0:
+		return "today"
+	case win.Days%7 == 0:
+		n, unit = win.Days/7, "week"
+	}
+
+	if n != 1 {
+		unit += "s"
+	}
+
+	return fmt.Sprintf("%d %s ago", n, unit)
+}
+
+// WriteOnThisDay writes the entries written on the same month and day as date
+// in previous years, followed by the entries written on the day each window
+// ends before date. Each entry is listed with its title, labels, and excerpt.
+func (j Journal) WriteOnThisDay(w io.Writer, date time.Time, windows []Window, setters ...WriterOption) error {
+	opts := newWriterOptions(setters)
+
+	baseHeadingDelim := strings.Repeat("#", opts.Level)
+	writeEntries := func(heading string, entries []Entry) {
+		if len(entries) == 0 {
+			return
+		}
+
+		fmt.Fprintf(w, "\n%s %s\n", baseHeadingDelim, heading)
+		for _, entry := range entries {
+			title := entry.Title()
+			if title == "" {
+				title = opts.Locale.Format(entry.Time, opts.DayLayout)
+			}
+			fmt.Fprintf(w, "\n* [%s](%s)", title, opts.link(entry.File))
+			if labels := entry.Labels(); len(labels) > 0 {
+				fmt.Fprintf(w, " - %s", strings.Join(labels, ", "))
+			}
+			fmt.Fprintln(w)
+
+			if entry.Excerpt != "" {
+				fmt.Fprintf(w, "\n  > %s\n", strings.ReplaceAll(entry.Excerpt, "\n", "\n  > "))
+			}
+		}
+	}
+
+	// Entries are sorted by file, which need not be chronological when they
+	// are spread over several directories.
+	var matches []Entry
+	for _, entry := range j.Entries {
+		if entry.Time.Month() == date.Month() && entry.Time.Day() == date.Day() && entry.Time.Year() < date.Year() {
+			matches = append(matches, entry)
+		}
+	}
+	sort.SliceStable(matches, func(a, b int) bool {
+		return matches[a].Time.After(matches[b].Time)
+	})
+
+	var previous [][]Entry
+	for _, entry := range matches {
+		if n := len(previous); n == 0 || previous[n-1][0].Time.Year() != entry.Time.Year() {
+			previous = append(previous, nil)
+		}
+		previous[len(previous)-1] = append(previous[len(previous)-1], entry)
+	}
+
+	for _, entries := range previous {
+		year := entries[0].Time
+		heading := fmt.Sprintf("%s (%s)", opts.Locale.Format(year, opts.YearLayout), Window{Years: date.Year() - year.Year()})
+		writeEntries(heading, entries)
+	}
+
+	for _, win := range windows {
+		day := win.Before(date).Format(dateFormat)
+
+		var entries []Entry
+		for _, entry := range j.Entries {
+			if entry.Time.Format(dateFormat) == day {
+				entries = append(entries, entry)
+			}
+		}
+
+		heading := fmt.Sprintf("%s (%s)", strings.Title(win.String()), day)
+		writeEntries(heading, entries)
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package journal
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteOnThisDay(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"old/2022-03-15.md":   "# Two Years\n\nFirst paragraph\nspans lines.\n\nSecond paragraph.",
+		"2022-03-15-trip.md":  "# Trip",
+		"2023-03-15-retro.md": "```\ncode\n```\n\n:work: and :fun: :work:",
+		"2023-03-16.md":       "# Wrong Day",
+		"2024-03-08.md":       "# Last Week",
+		"2024-03-15.md":       "# Today",
+	}
+
+	var tags TagLines
+	p := NewFileParser()
+	for name, content := range files {
+		file := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		lines, err := p.Parse(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tags = append(tags, lines...)
+	}
+
+	var b bytes.Buffer
+	j := NewJournal(tags)
+	date := time.Date(2024, time.March, 15, 0, 0, 0, 0, time.UTC)
+	windows := []Window{{Days: 7}, {Months: 1}}
+	if err := j.WriteOnThisDay(&b, date, windows, LinkDir(dir)); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `
+# 2023 (1 year ago)
+
+* [Retro](2023-03-15-retro.md) - work, fun
+
+  > :work: and :fun: :work:
+
+# 2022 (2 years ago)
+
+* [Two Years](old/2022-03-15.md)
+
+  > First paragraph
+  > spans lines.
+
+* [Trip](2022-03-15-trip.md)
+
+# 1 Week Ago (2024-03-08)
+
+* [Last Week](2024-03-08.md)
+`
+	if actual := strings.TrimSpace(b.String()); actual != strings.TrimSpace(expected) {
+		t.Errorf("expected:\n%s\nactual:\n%s", expected, actual)
+	}
+}
+
+func TestParseWindow(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected string
+	}{
+		{`1d`, `1 day ago`},
+		{`2w`, `2 weeks ago`},
+		{`14d`, `2 weeks ago`},
+		{`6m`, `6 months ago`},
+		{`1y`, `1 year ago`},
+		{`0d`, `today`},
+	}
+
+	for _, tc := range cases {
+		win, err := ParseWindow(tc.input)
+		if err != nil {
+			t.Errorf("%s: %v", tc.input, err)
+		} else if win.String() != tc.expected {
+			t.Errorf("%s: got %q, expected %q", tc.input, win, tc.expected)
+		}
+	}
+
+	if _, err := ParseWindow("week"); err == nil {
+		t.Errorf("expected error for invalid window")
+	}
+}
@@ -1,8 +1,14 @@
 package journal
 
 import (
-	"fmt"
+	"bytes"
+	"context"
 	"io/ioutil"
+	"net/url"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
 
 	"github.com/taylorskalyo/markdown-journal/ctags"
 	"github.com/taylorskalyo/markdown-journal/markdown/extension"
@@ -10,12 +16,16 @@ import (
 	"github.com/yuin/goldmark"
 	gast "github.com/yuin/goldmark/ast"
 	gextension "github.com/yuin/goldmark/extension"
+	east "github.com/yuin/goldmark/extension/ast"
 	"github.com/yuin/goldmark/parser"
 	"github.com/yuin/goldmark/text"
 	"github.com/yuin/goldmark/util"
 )
 
-// FileParser parses entry file markdown contents into ctags tags.
+// moreMarker separates an entry's summary from the rest of its contents.
+const moreMarker = "<!-- more -->"
+
+// FileParser parses entry file markdown contents.
 type FileParser struct {
 	parser.Parser
 }
@@ -32,6 +42,9 @@ func NewFileParser() FileParser {
 	p.AddOptions(parser.WithInlineParsers(
 		util.Prioritized(gextension.NewTaskCheckBoxParser(), 0),
 	))
+	p.AddOptions(parser.WithInlineParsers(
+		util.Prioritized(extension.NewWikiLinkParser(), 199),
+	))
 
 	return FileParser{
 		Parser: p,
@@ -45,10 +58,341 @@ func (p FileParser) Parse(filename string) (lines []ctags.TagLine, err error) {
 		return lines, err
 	}
 
-	return p.parse(filename, source)
+	e, err := p.parse(Entry{File: filename}, source)
+
+	return e.Tags(), err
+}
+
+// ParseEntry parses the given journal entry file.
+func (p FileParser) ParseEntry(filename string) (Entry, error) {
+	source, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	return p.ParseEntrySource(filename, source)
+}
+
+// ParseEntrySource parses the contents of the given journal entry file, such as
+// an editor's unsaved changes, instead of reading the file.
+func (p FileParser) ParseEntrySource(filename string, source []byte) (Entry, error) {
+	e, err := NewEntry(filename)
+	if err != nil {
+		return e, err
+	}
+
+	return p.parse(e, source)
+}
+
+// ParseFiles parses the given entries into ctags tags using up to jobs
+// concurrent parsers. If jobs is less than 1, GOMAXPROCS parsers are used.
+// Tags are returned in the order of the files, regardless of scheduling.
+//
+// A file that cannot be parsed does not prevent the others from being parsed.
+// The tags of all other files are returned along with a Diagnostics error
+// describing each failure.
+func ParseFiles(files []string, jobs int) (lines []ctags.TagLine, err error) {
+	entries, err := parseEntries(context.Background(), files, jobs)
+	for _, e := range entries {
+		lines = append(lines, e.Tags()...)
+	}
+
+	return lines, err
+}
+
+// ParseEntries parses the given journal entry files like ParseFiles, returning
+// entries instead of ctags tags.
+func ParseEntries(files []string, jobs int) ([]Entry, error) {
+	return parseEntries(context.Background(), files, jobs)
+}
+
+// parseEntries is ParseEntries, stopping early with the context's error if the
+// context is done.
+func parseEntries(ctx context.Context, files []string, jobs int) (entries []Entry, err error) {
+	if jobs < 1 {
+		jobs = runtime.GOMAXPROCS(0)
+	}
+	if jobs > len(files) {
+		jobs = len(files)
+	}
+
+	type result struct {
+		entry Entry
+		err   error
+	}
+	results := make([]result, len(files))
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < jobs; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			p := NewFileParser()
+			for i := range indexes {
+				e, err := p.ParseEntry(files[i])
+				results[i] = result{e, err}
+			}
+		}()
+	}
+
+	for i := range files {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var diagnostics Diagnostics
+	for i, r := range results {
+		if r.err != nil {
+			diagnostics = append(diagnostics, fileDiagnostic(files[i], r.err))
+			continue
+		}
+		entries = append(entries, r.entry)
+	}
+	if len(diagnostics) > 0 {
+		return entries, diagnostics
+	}
+
+	return entries, nil
+}
+
+// excerpt returns the summary of an entry and the offset at which it begins.
+// The summary is the content preceding a "<!-- more -->" marker, or else the
+// first paragraph. Leading headings are not included.
+func excerpt(tree gast.Node, source []byte) (summary string, offset int) {
+	offset = -1
+	for n := tree.FirstChild(); n != nil; n = n.NextSibling() {
+		if block, ok := n.(*gast.HTMLBlock); ok && block.Lines().Len() > 0 {
+			segment := block.Lines().At(0)
+			if strings.TrimSpace(string(segment.Value(source))) == moreMarker {
+				if offset < 0 {
+					return "", -1
+				}
+				return strings.TrimSpace(string(source[offset:segment.Start])), offset
+			}
+		}
+
+		if _, ok := n.(*gast.Heading); ok && offset < 0 {
+			continue
+		}
+
+		if offset < 0 {
+			offset = lineStart(source, blockStart(n, source))
+		}
+	}
+
+	for n := tree.FirstChild(); n != nil; n = n.NextSibling() {
+		if paragraph, ok := n.(*gast.Paragraph); ok {
+			var lines []string
+			for i := 0; i < paragraph.Lines().Len(); i++ {
+				segment := paragraph.Lines().At(i)
+				lines = append(lines, strings.TrimSpace(string(segment.Value(source))))
+			}
+			return strings.Join(lines, "\n"), paragraph.Lines().At(0).Start
+		}
+	}
+
+	return "", -1
 }
 
-func (p FileParser) parse(filename string, source []byte) (lines []ctags.TagLine, err error) {
+// blockStart returns the offset of the first line of a block node.
+func blockStart(n gast.Node, source []byte) int {
+	if _, ok := n.(*gast.FencedCodeBlock); ok && n.Lines().Len() > 0 {
+		// Lines exclude the opening fence, which is on the preceding line.
+		return lineStart(source, n.Lines().At(0).Start) - 1
+	}
+
+	for ; n != nil; n = n.FirstChild() {
+		if n.Type() == gast.TypeBlock && n.Lines().Len() > 0 {
+			return n.Lines().At(0).Start
+		}
+	}
+
+	return 0
+}
+
+// firstSegment returns the segment of the first text within a node.
+func firstSegment(n gast.Node) (text.Segment, bool) {
+	for c := n.FirstChild(); c != nil; c = c.FirstChild() {
+		if t, ok := c.(*gast.Text); ok {
+			return t.Segment, true
+		}
+	}
+
+	return text.Segment{}, false
+}
+
+// inlineSegment returns a segment that locates an inline node: its first text,
+// or, if it has none (e.g. an image without alt text), the first line of the
+// block that contains it.
+func inlineSegment(n gast.Node) (text.Segment, bool) {
+	if segment, ok := firstSegment(n); ok {
+		return segment, true
+	}
+
+	for p := n.Parent(); p != nil; p = p.Parent() {
+		if p.Type() == gast.TypeBlock && p.Lines().Len() > 0 {
+			return p.Lines().At(0), true
+		}
+	}
+
+	return text.Segment{}, false
+}
+
+// localLink resolves the destination of a markdown link or image found in the
+// given file. It returns false if the destination is not a relative link to a
+// file, e.g. a URL or a link to an anchor in the same file.
+func localLink(filename, destination string) (string, bool) {
+	if strings.Contains(destination, ":") {
+		return "", false
+	}
+
+	if i := strings.IndexAny(destination, "#?"); i >= 0 {
+		destination = destination[:i]
+	}
+
+	destination, err := url.PathUnescape(destination)
+	if err != nil || destination == "" {
+		return "", false
+	}
+
+	target := filepath.FromSlash(destination)
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(filepath.Dir(filename), target)
+	}
+
+	return filepath.Clean(target), true
+}
+
+// sections returns the content of the section each heading starts, from the
+// line after the heading to the line before the next heading of equal or
+// higher level. The section keyed by nil is the content preceding the first
+// heading.
+func sections(tree gast.Node, source []byte) map[*gast.Heading]LineRange {
+	lastLine := bytes.Count(source, []byte("\n"))
+	if len(source) > 0 && source[len(source)-1] != '\n' {
+		lastLine++
+	}
+
+	var headings []*gast.Heading
+	var lines []int
+	gast.Walk(tree, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if h, ok := n.(*gast.Heading); ok && entering && h.Lines().Len() > 0 {
+			headings = append(headings, h)
+			lines = append(lines, lineNumber(source, h.Lines().At(0).Start))
+		}
+		return gast.WalkContinue, nil
+	})
+
+	result := map[*gast.Heading]LineRange{nil: {1, lastLine}}
+	if len(headings) > 0 {
+		result[nil] = LineRange{1, lines[0] - 1}
+	}
+
+	for i, h := range headings {
+		// Setext headings are followed by an underline.
+		last := lineNumber(source, h.Lines().At(h.Lines().Len()-1).Start)
+		if !bytes.HasPrefix(bytes.TrimSpace(source[lineStart(source, h.Lines().At(0).Start):]), []byte("#")) {
+			last++
+		}
+
+		s := LineRange{last + 1, lastLine}
+		for j := i + 1; j < len(headings); j++ {
+			if headings[j].Level <= h.Level {
+				s.End = lines[j] - 1
+				break
+			}
+		}
+		result[h] = s
+	}
+
+	return result
+}
+
+// blankFrontMatter returns a copy of source with YAML front matter, as found
+// by frontMatter, replaced by spaces. Front matter is then not parsed as
+// markdown, but offsets and line numbers are unchanged. Source without front
+// matter is returned as is.
+func blankFrontMatter(source []byte) []byte {
+	lines := splitLines(string(source))
+	_, n := frontMatter(lines)
+	if n == 0 {
+		return source
+	}
+
+	blank := make([]byte, len(source))
+	copy(blank, source)
+	for i, c := range blank[:len(strings.Join(lines[:n], ""))] {
+		if c != '\n' {
+			blank[i] = ' '
+		}
+	}
+
+	return blank
+}
+
+// countWords returns the number of whitespace-separated words in the text of
+// a markdown document, without front matter and markdown syntax.
+func (p FileParser) countWords(source []byte) int {
+	source = blankFrontMatter(source)
+
+	var b bytes.Buffer
+	gast.Walk(p.Parser.Parse(text.NewReader(source)), func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if !entering {
+			if n.Type() == gast.TypeBlock {
+				b.WriteByte('\n')
+			}
+			return gast.WalkContinue, nil
+		}
+
+		switch v := n.(type) {
+		case *gast.Text:
+			b.Write(v.Segment.Value(source))
+			if v.SoftLineBreak() || v.HardLineBreak() {
+				b.WriteByte('\n')
+			}
+		case *gast.String:
+			b.Write(v.Value)
+		case *ast.Label:
+			b.Write(v.Value.Segment.Value(source))
+		case *ast.WikiLink:
+			b.Write(v.Value.Segment.Value(source))
+		case *gast.CodeBlock, *gast.FencedCodeBlock:
+			lines := n.Lines()
+			for i := 0; i < lines.Len(); i++ {
+				line := lines.At(i)
+				b.Write(line.Value(source))
+			}
+		}
+
+		return gast.WalkContinue, nil
+	})
+
+	return len(strings.Fields(b.String()))
+}
+
+// lineNumber returns the line number of the given offset, starting at 1.
+func lineNumber(source []byte, offset int) int {
+	return bytes.Count(source[:offset], []byte("\n")) + 1
+}
+
+// lineStart returns the offset of the beginning of the line containing the
+// given offset.
+func lineStart(source []byte, offset int) int {
+	return bytes.LastIndexByte(source[:offset], '\n') + 1
+}
+
+func (p FileParser) parse(e Entry, source []byte) (Entry, error) {
+	source = blankFrontMatter(source)
 	reader := text.NewReader(source)
 	tree := p.Parser.Parse(reader)
 
@@ -56,51 +400,106 @@ func (p FileParser) parse(filename string, source []byte) (lines []ctags.TagLine
 	reader.SetPosition(0, text.Segment{})
 	reader.ResetPosition()
 
-	isTitleFound := false
+	labelSections := sections(tree, source)
+	headingText := func(h *gast.Heading) string {
+		if h == nil {
+			return ""
+		}
+		return string(h.Text(source))
+	}
+
+	_, pos := reader.Position()
+	lineOf := func(segment text.Segment) int {
+		reader.Advance(segment.Start - pos.Start)
+		var line int
+		line, pos = reader.Position()
+		return line + 1
+	}
 
-	line, pos := reader.Position()
-	err = gast.Walk(tree, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+	err := gast.Walk(tree, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
 		s := gast.WalkStatus(gast.WalkContinue)
 
 		if !entering {
 			return s, nil
 		}
 
-		var segment text.Segment
-		var tagFields = ctags.TagFields{}
-
 		switch v := n.(type) {
 		case *ast.Label:
-			segment = v.Value.Segment
-			if v.Heading != nil {
-				heading := string(v.Heading.Text(reader.Source()))
-				tagFields["heading"] = heading
+			o := LabelOccurrence{
+				Name:    string(v.Text(source)),
+				File:    e.File,
+				Line:    lineOf(v.Value.Segment),
+				Heading: headingText(v.Heading),
 			}
-			tagFields["kind"] = "label"
+			o.Section = labelSections[v.Heading]
+			e.Occurrences = append(e.Occurrences, o)
 		case *gast.Heading:
-			if isTitleFound {
+			e.Headings = append(e.Headings, Heading{
+				Text:  string(v.Text(source)),
+				Level: v.Level,
+				Line:  lineOf(v.Lines().At(0)),
+			})
+		case *east.TaskCheckBox:
+			block := v.Parent()
+			if block == nil || block.Lines().Len() == 0 {
 				return s, nil
 			}
-			isTitleFound = true
-			segment = v.Lines().At(0)
-			tagFields["kind"] = "title"
-		default:
-			return s, nil
-		}
-
-		reader.Advance(segment.Start - pos.Start)
-		line, pos = reader.Position()
-		tagFields["line"] = fmt.Sprintf("%d", line+1)
-		tl := ctags.TagLine{
-			TagName:    string(n.Text(reader.Source())),
-			TagFile:    filename,
-			TagAddress: fmt.Sprintf("%d", line+1),
-			TagFields:  tagFields,
+			var lines []string
+			for i := 0; i < block.Lines().Len(); i++ {
+				segment := block.Lines().At(i)
+				lines = append(lines, strings.TrimSpace(string(segment.Value(source))))
+			}
+			task := strings.Join(lines, " ")
+			task = strings.TrimSpace(task[strings.Index(task, "]")+1:])
+			e.Tasks = append(e.Tasks, Task{
+				Text:    task,
+				Done:    v.IsChecked,
+				File:    e.File,
+				Line:    lineOf(block.Lines().At(0)),
+				Heading: headingText(extension.Heading(v)),
+			})
+		case *ast.WikiLink:
+			e.Links = append(e.Links, Link{
+				Destination: string(v.Destination),
+				Target:      string(v.Destination),
+				Style:       WikiLink,
+				File:        e.File,
+				Line:        lineOf(v.Value.Segment),
+				Heading:     headingText(v.Heading),
+			})
+		case *gast.Link, *gast.Image:
+			var destination string
+			switch v := v.(type) {
+			case *gast.Link:
+				destination = string(v.Destination)
+			case *gast.Image:
+				destination = string(v.Destination)
+			}
+			target, ok := localLink(e.File, destination)
+			if !ok {
+				return s, nil
+			}
+			segment, ok := inlineSegment(n)
+			if !ok {
+				return s, nil
+			}
+			e.Links = append(e.Links, Link{
+				Destination: destination,
+				Target:      target,
+				Style:       MarkdownLink,
+				File:        e.File,
+				Line:        lineOf(segment),
+				Heading:     headingText(extension.Heading(n)),
+			})
 		}
-		lines = append(lines, tl)
 
 		return s, nil
 	})
 
-	return lines, err
+	if summary, offset := excerpt(tree, source); summary != "" {
+		e.Excerpt = summary
+		e.excerptLine = lineNumber(source, offset)
+	}
+
+	return e, err
 }
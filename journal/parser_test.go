@@ -2,8 +2,14 @@ package journal
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/taylorskalyo/markdown-journal/ctags"
 )
@@ -37,8 +43,33 @@ Actual Output:
 :bar:
 			`,
 			`
-Foo	2006-01-02.md	2;"	kind:title	line:2
-bar	2006-01-02.md	4;"	heading:Foo	kind:label	line:4
+Foo	2006-01-02.md	2;"	kind:title	level:1	line:2
+bar	2006-01-02.md	4;"	heading:Foo	kind:label	line:4	section:3-5
+2006-01-02	2006-01-02.md	4;"	excerpt::bar:	kind:excerpt	line:4
+			`,
+		},
+		{
+			`decomposed accents in labels`,
+			`2006-01-02.md`,
+			"# Foo\n\n:cafe\u0301:",
+			"\n" +
+				"Foo\t2006-01-02.md\t1;\"\tkind:title\tlevel:1\tline:1\n" +
+				"cafe\u0301\t2006-01-02.md\t3;\"\theading:Foo\tkind:label\tline:3\tsection:2-3\n" +
+				"2006-01-02\t2006-01-02.md\t3;\"\texcerpt::cafe\u0301:\tkind:excerpt\tline:3\n",
+		},
+		{
+			`label sections`,
+			`2006-01-02.md`,
+			"# Day\n\n:a:\n\n## Work\n\n:b:\n\n### Sub\n\nmore\n\nHome\n----\n\n:c:\n",
+			`
+Day	2006-01-02.md	1;"	kind:title	level:1	line:1
+a	2006-01-02.md	3;"	heading:Day	kind:label	line:3	section:2-16
+2006-01-02	2006-01-02.md	3;"	excerpt::a:	kind:excerpt	line:3
+Work	2006-01-02.md	5;"	kind:heading	level:2	line:5
+b	2006-01-02.md	7;"	heading:Work	kind:label	line:7	section:6-12
+Sub	2006-01-02.md	9;"	kind:heading	level:3	line:9
+Home	2006-01-02.md	13;"	kind:heading	level:2	line:13
+c	2006-01-02.md	16;"	heading:Home	kind:label	line:16	section:15-16
 			`,
 		},
 		{
@@ -46,7 +77,7 @@ bar	2006-01-02.md	4;"	heading:Foo	kind:label	line:4
 			`2006-01-02.md`,
 			"# Foo\n```\n:bar:\n```",
 			`
-Foo	2006-01-02.md	1;"	kind:title	line:1
+Foo	2006-01-02.md	1;"	kind:title	level:1	line:1
 			`,
 		},
 		{
@@ -56,8 +87,92 @@ Foo	2006-01-02.md	1;"	kind:title	line:1
 # Foo :bar:
 			`,
 			`
-Foo bar	2006-01-02.md	2;"	kind:title	line:2
-bar	2006-01-02.md	2;"	heading:Foo bar	kind:label	line:2
+Foo bar	2006-01-02.md	2;"	kind:title	level:1	line:2
+bar	2006-01-02.md	2;"	heading:Foo bar	kind:label	line:2	section:3-3
+			`,
+		},
+		{
+			`links`,
+			`diary/2006-01-02.md`,
+			"# Foo\n\n```\n[[code]]\n```\n\n[[2006-01-01|yesterday]] [bar](../bar.md#baz) [web](https://example.com/a.md)\n\n![](img/a%20b.png) [doc](doc.pdf) [top](#foo)",
+			`
+Foo	diary/2006-01-02.md	1;"	kind:title	level:1	line:1
+2006-01-01	diary/2006-01-02.md	7;"	heading:Foo	kind:link	line:7	style:wiki	target:2006-01-01
+../bar.md#baz	diary/2006-01-02.md	7;"	heading:Foo	kind:link	line:7	style:markdown	target:bar.md
+2006-01-02	diary/2006-01-02.md	7;"	excerpt:[[2006-01-01|yesterday]] [bar](../bar.md#baz) [web](https://example.com/a.md)	kind:excerpt	line:7
+img/a%20b.png	diary/2006-01-02.md	9;"	heading:Foo	kind:link	line:9	style:markdown	target:diary/img/a b.png
+doc.pdf	diary/2006-01-02.md	9;"	heading:Foo	kind:link	line:9	style:markdown	target:diary/doc.pdf
+			`,
+		},
+		{
+			`tasks`,
+			`2006-01-02.md`,
+			"# Foo\n\n## Todo\n\n- [x] Write the report\n- [ ] Review changes :review:\n- not a task",
+			`
+Foo	2006-01-02.md	1;"	kind:title	level:1	line:1
+Todo	2006-01-02.md	3;"	kind:heading	level:2	line:3
+Write the report	2006-01-02.md	5;"	done:true	heading:Todo	kind:task	line:5
+review	2006-01-02.md	6;"	heading:Todo	kind:label	line:6	section:4-7
+Review changes :review:	2006-01-02.md	6;"	done:false	heading:Todo	kind:task	line:6
+			`,
+		},
+		{
+			`front matter`,
+			`2006-01-02.md`,
+			"---\ncreated: 2006-01-02T15:04:05Z\nnote: :notalabel:\n---\n# Foo\n\n:bar:",
+			`
+Foo	2006-01-02.md	5;"	kind:title	level:1	line:5
+bar	2006-01-02.md	7;"	heading:Foo	kind:label	line:7	section:6-7
+2006-01-02	2006-01-02.md	7;"	excerpt::bar:	kind:excerpt	line:7
+			`,
+		},
+		{
+			`unterminated front matter`,
+			`2006-01-02.md`,
+			"---\n:bar:",
+			`
+bar	2006-01-02.md	2;"	kind:label	line:2	section:1-2
+2006-01-02	2006-01-02.md	2;"	excerpt::bar:	kind:excerpt	line:2
+			`,
+		},
+		{
+			`leading thematic break`,
+			`2006-01-02.md`,
+			"---\n\n# Monday\n\n:work:\n\nNotes\n---\n\n:home:\n",
+			`
+Monday	2006-01-02.md	3;"	kind:title	level:1	line:3
+work	2006-01-02.md	5;"	heading:Monday	kind:label	line:5	section:4-10
+2006-01-02	2006-01-02.md	5;"	excerpt::work:	kind:excerpt	line:5
+Notes	2006-01-02.md	7;"	kind:heading	level:2	line:7
+home	2006-01-02.md	10;"	heading:Notes	kind:label	line:10	section:9-10
+			`,
+		},
+		{
+			`setext heading after front matter`,
+			`2006-01-02.md`,
+			"---\ncreated: 2006-01-02T15:04:05Z\n---\nNotes\n---\n\n:home:\n",
+			`
+Notes	2006-01-02.md	4;"	kind:title	level:2	line:4
+home	2006-01-02.md	7;"	heading:Notes	kind:label	line:7	section:6-7
+2006-01-02	2006-01-02.md	7;"	excerpt::home:	kind:excerpt	line:7
+			`,
+		},
+		{
+			`excerpt from first paragraph`,
+			`2006-01-02-foo.md`,
+			"# Foo\n\n- list\n\nFirst\nparagraph.\n\nSecond paragraph.",
+			`
+Foo	2006-01-02-foo.md	1;"	kind:title	level:1	line:1
+2006-01-02-foo	2006-01-02-foo.md	5;"	excerpt:First\nparagraph.	kind:excerpt	line:5
+			`,
+		},
+		{
+			`excerpt before more marker`,
+			`2006-01-02.md`,
+			"# Foo\n\n```\ncode\n```\n\nIntro.\n\n<!-- more -->\n\nRest.",
+			`
+Foo	2006-01-02.md	1;"	kind:title	level:1	line:1
+2006-01-02	2006-01-02.md	3;"	excerpt:` + "```" + `\ncode\n` + "```" + `\n\nIntro.	kind:excerpt	line:3
 			`,
 		},
 	}
@@ -66,9 +181,9 @@ bar	2006-01-02.md	2;"	heading:Foo bar	kind:label	line:2
 		var b bytes.Buffer
 
 		p := NewFileParser()
-		lines, _ := p.parse(tc.filename, []byte(tc.input))
+		e, _ := p.parse(Entry{File: tc.filename}, []byte(tc.input))
 		w := ctags.NewWriter(&b)
-		w.WriteAll(lines)
+		w.WriteAll(e.Tags())
 
 		actual := strings.TrimSpace(b.String())
 		expected := strings.TrimSpace(tc.expected)
@@ -77,3 +192,83 @@ bar	2006-01-02.md	2;"	heading:Foo bar	kind:label	line:2
 		}
 	}
 }
+
+func TestParseFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	var files []string
+	for day := 1; day <= 20; day++ {
+		file := filepath.Join(dir, fmt.Sprintf("2006-01-%02d.md", day))
+		if err := ioutil.WriteFile(file, []byte(fmt.Sprintf("# Day %d\n", day)), 0644); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, file)
+	}
+	missing := filepath.Join(dir, "2006-02-01.md")
+	files = append(files[:10], append([]string{missing}, files[10:]...)...)
+
+	for _, jobs := range []int{0, 1, 4, 100} {
+		lines, err := ParseFiles(files, jobs)
+
+		var diagnostics Diagnostics
+		if !errors.As(err, &diagnostics) || len(diagnostics) != 1 || diagnostics[0].File != missing {
+			t.Errorf("jobs %d: expected error for %s, got %v", jobs, missing, err)
+		}
+
+		var titles []string
+		for _, l := range lines {
+			titles = append(titles, l.TagName)
+		}
+		if len(titles) != 20 || titles[0] != "Day 1" || titles[10] != "Day 11" || titles[19] != "Day 20" {
+			t.Errorf("jobs %d: unexpected tags: %v", jobs, titles)
+		}
+	}
+}
+
+// BenchmarkParseFiles parses a synthetic journal of 10,000 entries.
+func BenchmarkParseFiles(b *testing.B) {
+	dir := b.TempDir()
+
+	var files []string
+	day := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 10000; i++ {
+		file := filepath.Join(dir, day.AddDate(0, 0, i).Format(dateFormat)+".md")
+		source := fmt.Sprintf(benchmarkEntry, i, i%50, i%7)
+		if err := ioutil.WriteFile(file, []byte(source), 0644); err != nil {
+			b.Fatal(err)
+		}
+		files = append(files, file)
+	}
+
+	jobs := []int{1}
+	if n := runtime.GOMAXPROCS(0); n > 1 {
+		jobs = append(jobs, n)
+	}
+
+	for _, jobs := range jobs {
+		b.Run(fmt.Sprintf("jobs=%d", jobs), func(b *testing.B) {
+			for n := 0; n < b.N; n++ {
+				if _, err := ParseFiles(files, jobs); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+const benchmarkEntry = `# Entry %d
+
+Some thoughts about the day, with a [[2000-01-01]] link and a
+[markdown link](2000-01-02.md).
+
+## Work :project-%d:
+
+- [x] Write the report
+- [ ] Review changes :review:
+
+## Home :day-%d:
+
+` + "```" + `
+:not-a-label:
+` + "```" + `
+`
@@ -0,0 +1,149 @@
+package journal
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/taylorskalyo/markdown-journal/markdown/extension/ast"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// diffContext is the number of unchanged lines shown around each change in a
+// diff.
+const diffContext = 3
+
+// LabelRewrite is the result of renaming labels in a file.
+type LabelRewrite struct {
+	File  string
+	Old   []byte
+	New   []byte
+	Count int
+}
+
+// RewriteLabels renames labels in the given file. renames maps old label
+// names to new ones. Only label occurrences recognized by the parser are
+// renamed, so text in code blocks, URLs, etc. is left untouched. The file itself
+// is not modified.
+func (p FileParser) RewriteLabels(filename string, renames map[string]string) (r LabelRewrite, err error) {
+	source, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return r, err
+	}
+
+	r = p.rewriteLabels(source, renames)
+	r.File = filename
+
+	return r, nil
+}
+
+func (p FileParser) rewriteLabels(source []byte, renames map[string]string) (r LabelRewrite) {
+	var segments []text.Segment
+
+	// Parse the source without front matter, as the parser does, but splice
+	// replacements into the original source.
+	parsed := blankFrontMatter(source)
+	tree := p.Parser.Parse(text.NewReader(parsed))
+	gast.Walk(tree, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if label, ok := n.(*ast.Label); ok && entering {
+			name := string(label.Value.Segment.Value(parsed))
+			if _, ok := renames[name]; ok {
+				segments = append(segments, label.Value.Segment)
+			}
+		}
+		return gast.WalkContinue, nil
+	})
+
+	sort.Slice(segments, func(i, j int) bool {
+		return segments[i].Start < segments[j].Start
+	})
+
+	var b bytes.Buffer
+	prev := 0
+	for _, segment := range segments {
+		b.Write(source[prev:segment.Start])
+		b.WriteString(renames[string(segment.Value(source))])
+		prev = segment.Stop
+	}
+	b.Write(source[prev:])
+
+	return LabelRewrite{
+		Old:   source,
+		New:   b.Bytes(),
+		Count: len(segments),
+	}
+}
+
+// Diff returns a unified diff of the rewrite. Since renaming labels never adds
+// or removes lines, changed lines are compared in place.
+func (r LabelRewrite) Diff() string {
+	if r.Count == 0 {
+		return ""
+	}
+
+	a := splitLines(string(r.Old))
+	b := splitLines(string(r.New))
+
+	var changed []int
+	for i := range a {
+		if a[i] != b[i] {
+			changed = append(changed, i)
+		}
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- a/%s\n+++ b/%s\n", r.File, r.File)
+
+	for i := 0; i < len(changed); {
+		// Extend the hunk while the next change falls within its context.
+		j := i
+		for j+1 < len(changed) && changed[j+1]-changed[j] <= 2*diffContext {
+			j++
+		}
+
+		start := changed[i] - diffContext
+		if start < 0 {
+			start = 0
+		}
+		end := changed[j] + diffContext + 1
+		if end > len(a) {
+			end = len(a)
+		}
+
+		fmt.Fprintf(&out, "@@ -%d,%d +%d,%d @@\n", start+1, end-start, start+1, end-start)
+		for k := start; k < end; k++ {
+			if a[k] == b[k] {
+				out.WriteString(" " + diffLine(a[k]))
+				continue
+			}
+			out.WriteString("-" + diffLine(a[k]))
+			out.WriteString("+" + diffLine(b[k]))
+		}
+
+		i = j + 1
+	}
+
+	return out.String()
+}
+
+// diffLine terminates a line for use in a diff.
+func diffLine(line string) string {
+	if strings.HasSuffix(line, "\n") {
+		return line
+	}
+
+	return line + "\n\\ No newline at end of file\n"
+}
+
+// splitLines splits s after each newline.
+func splitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
@@ -0,0 +1,102 @@
+package journal
+
+import (
+	"testing"
+)
+
+func TestRewriteLabels(t *testing.T) {
+	format := `
+============= case %s ================
+Markdown Input:
+-----------
+%v
+Expected Output:
+----------
+%v
+Actual Output:
+----------
+%v
+`
+
+	cases := []struct {
+		name     string
+		input    string
+		renames  map[string]string
+		expected string
+		diff     string
+	}{
+		{
+			`rename`,
+			"# Foo :mtg:\n\n:mtg: :other: https://example.com:mtg:\n\n```\n:mtg:\n```\n\n`:mtg:`\n",
+			map[string]string{"mtg": "meeting"},
+			"# Foo :meeting:\n\n:meeting: :other: https://example.com:mtg:\n\n```\n:mtg:\n```\n\n`:mtg:`\n",
+			`--- a/2006-01-02.md
++++ b/2006-01-02.md
+@@ -1,6 +1,6 @@
+-# Foo :mtg:
++# Foo :meeting:
+ 
+-:mtg: :other: https://example.com:mtg:
++:meeting: :other: https://example.com:mtg:
+ 
+ ` + "```" + `
+ :mtg:
+`,
+		},
+		{
+			`merge`,
+			"1\n2\n3\n4\n5\n6\n7\n8\n9\n:a:\n11\n:b:",
+			map[string]string{"a": "c", "b": "c"},
+			"1\n2\n3\n4\n5\n6\n7\n8\n9\n:c:\n11\n:c:",
+			`--- a/2006-01-02.md
++++ b/2006-01-02.md
+@@ -7,6 +7,6 @@
+ 7
+ 8
+ 9
+-:a:
++:c:
+ 11
+-:b:
+\ No newline at end of file
++:c:
+\ No newline at end of file
+`,
+		},
+		{
+			`front matter`,
+			"---\nnote: :work:\n---\n# Foo\n\n:work:\n",
+			map[string]string{"work": "job"},
+			"---\nnote: :work:\n---\n# Foo\n\n:job:\n",
+			`--- a/2006-01-02.md
++++ b/2006-01-02.md
+@@ -3,4 +3,4 @@
+ ---
+ # Foo
+ 
+-:work:
++:job:
+`,
+		},
+		{
+			`no matches`,
+			":a:\n",
+			map[string]string{"b": "c"},
+			":a:\n",
+			``,
+		},
+	}
+
+	for _, tc := range cases {
+		p := NewFileParser()
+		r := p.rewriteLabels([]byte(tc.input), tc.renames)
+		r.File = "2006-01-02.md"
+
+		if actual := string(r.New); actual != tc.expected {
+			t.Errorf(format, tc.name, tc.input, tc.expected, actual)
+		}
+		if actual := r.Diff(); actual != tc.diff {
+			t.Errorf(format, tc.name+" diff", tc.input, tc.diff, actual)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package journal
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"time"
+)
+
+// EntryStats holds statistics for a single entry.
+type EntryStats struct {
+	Entry
+	Words int
+}
+
+// PeriodStats holds statistics for the entries within a period of time. Start
+// is the beginning of the period.
+type PeriodStats struct {
+	Start   time.Time
+	Entries int
+	Words   int
+}
+
+// Streak is a run of consecutive days with at least one entry.
+type Streak struct {
+	Start time.Time
+	End   time.Time
+	Days  int
+}
+
+// Stats summarizes writing activity in a journal.
+type Stats struct {
+	// Entries in chronological order.
+	Entries []EntryStats
+	Words   int
+
+	// Number of entries written on each day of the week, indexed by
+	// time.Weekday.
+	Weekdays [7]int
+
+	// CurrentStreak ends today, or yesterday if there is no entry for today
+	// yet. LongestStreak is the earliest of the longest streaks.
+	CurrentStreak Streak
+	LongestStreak Streak
+}
+
+// Stats computes statistics for the journal. Word counts are determined by
+// reading each entry file and counting the whitespace-separated words in its
+// text, without front matter and markdown syntax. today is used to determine
+// the current streak.
+//
+// Entries whose files cannot be read are skipped and returned as a Diagnostics
+// error, along with the statistics of the other entries.
+func (j Journal) Stats(today time.Time) (s Stats, err error) {
+	var diagnostics Diagnostics
+	p := NewFileParser()
+	entries := newWriterOptions([]WriterOption{EntryOrder(Ascending)}).entries(j.Entries)
+	for _, entry := range entries {
+		source, err := ioutil.ReadFile(entry.File)
+		if err != nil {
+			diagnostics = append(diagnostics, fileDiagnostic(entry.File, err))
+			continue
+		}
+
+		words := p.countWords(source)
+		s.Entries = append(s.Entries, EntryStats{Entry: entry, Words: words})
+		s.Words += words
+		s.Weekdays[entry.Time.Weekday()]++
+	}
+
+	var streak Streak
+	days, _ := s.Periods(GroupByDay)
+	for _, day := range days {
+		if streak.Days > 0 && day.Start.Equal(streak.End.AddDate(0, 0, 1)) {
+			streak.End = day.Start
+			streak.Days++
+		} else {
+			streak = Streak{Start: day.Start, End: day.Start, Days: 1}
+		}
+
+		if streak.Days > s.LongestStreak.Days {
+			s.LongestStreak = streak
+		}
+	}
+
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	if streak.End.Equal(today) || streak.End.Equal(today.AddDate(0, 0, -1)) {
+		s.CurrentStreak = streak
+	}
+
+	if len(diagnostics) > 0 {
+		return s, diagnostics
+	}
+
+	return s, nil
+}
+
+// Periods aggregates entries by day, ISO 8601 week, month, or year, in
+// chronological order.
+func (s Stats) Periods(g Grouping) (periods []PeriodStats, err error) {
+	start, err := periodStart(g)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range s.Entries {
+		t := start(entry.Time)
+		if n := len(periods); n == 0 || !periods[n-1].Start.Equal(t) {
+			periods = append(periods, PeriodStats{Start: t})
+		}
+		p := &periods[len(periods)-1]
+		p.Entries++
+		p.Words += entry.Words
+	}
+
+	return periods, nil
+}
+
+// WriteStats writes a markdown summary of the statistics. Activity is
+// aggregated by the given period (see Periods); if period is GroupByNone, each
+// entry is listed instead.
+func (s Stats) WriteStats(w io.Writer, period Grouping, setters ...WriterOption) error {
+	opts := newWriterOptions(setters)
+
+	baseHeadingDelim := strings.Repeat("#", opts.Level)
+	fmt.Fprintf(w, "\n%s Summary\n", baseHeadingDelim)
+	fmt.Fprintf(w, "* Entries: %d\n", len(s.Entries))
+	fmt.Fprintf(w, "* Words: %d", s.Words)
+	if len(s.Entries) > 0 {
+		fmt.Fprintf(w, " (%d per entry)", s.Words/len(s.Entries))
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "* Current streak: %s\n", formatStreak(s.CurrentStreak))
+	fmt.Fprintf(w, "* Longest streak: %s\n", formatStreak(s.LongestStreak))
+
+	fmt.Fprintf(w, "\n%s Busiest Weekdays\n", baseHeadingDelim)
+	days := []time.Weekday{}
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if s.Weekdays[d] > 0 {
+			days = append(days, d)
+		}
+	}
+	sort.SliceStable(days, func(i, j int) bool {
+		return s.Weekdays[days[i]] > s.Weekdays[days[j]]
+	})
+	for _, d := range days {
+		fmt.Fprintf(w, "* %s: %d\n", opts.Locale.Days[d], s.Weekdays[d])
+	}
+
+	periods, err := s.Periods(period)
+	if err != nil && period != GroupByNone {
+		return err
+	}
+
+	fmt.Fprintf(w, "\n%s Activity\n\n", baseHeadingDelim)
+	if period == GroupByNone {
+		fmt.Fprintf(w, "| Entry | Words |\n| --- | --- |\n")
+		for _, entry := range s.Entries {
+			name := entry.Title()
+			if name == "" {
+				name = entry.File
+			}
+			fmt.Fprintf(w, "| [%s](%s) | %d |\n", name, opts.link(entry.File), entry.Words)
+		}
+
+		return nil
+	}
+
+	fmt.Fprintf(w, "| Period | Entries | Words |\n| --- | --- | --- |\n")
+	for _, p := range periods {
+		fmt.Fprintf(w, "| %s | %d | %d |\n", p.Start.Format(dateFormat), p.Entries, p.Words)
+	}
+
+	return nil
+}
+
+// periodStart returns a function that determines the beginning of the day,
+// ISO 8601 week, month, or year containing a given time.
+func periodStart(g Grouping) (func(time.Time) time.Time, error) {
+	switch g {
+	case GroupByDay:
+		return func(t time.Time) time.Time { return t }, nil
+	case GroupByISOWeek:
+		return isoWeekStart, nil
+	case GroupByMonth:
+		return monthStart, nil
+	case GroupByYear:
+		return yearStart, nil
+	}
+
+	return nil, fmt.Errorf("unsupported period: %s", g)
+}
+
+func formatStreak(s Streak) string {
+	switch s.Days {
+	case 0:
+		return "0 days"
+	case 1:
+		return fmt.Sprintf("1 day (%s)", s.Start.Format(dateFormat))
+	}
+
+	return fmt.Sprintf("%d days (%s – %s)", s.Days, s.Start.Format(dateFormat), s.End.Format(dateFormat))
+}
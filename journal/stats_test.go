@@ -0,0 +1,119 @@
+package journal
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStats(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"2024-03-11.md":      "# Monday\n\nOne two three.",
+		"2024-03-12.md":      "# Tuesday\n\nFour five.",
+		"2024-03-12-more.md": "Six",
+		"2024-03-13.md":      "# Wednesday",
+		"2024-03-20.md":      "---\nmood: good\n---\n# Later\n\n:label: *words*",
+	}
+
+	var j Journal
+	for name, content := range files {
+		file := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		e, err := NewEntry(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		j.Entries = append(j.Entries, e)
+	}
+
+	// Entries whose files cannot be read are skipped.
+	missing, err := NewEntry(filepath.Join(dir, "2024-03-14.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	j.Entries = append(j.Entries, missing)
+
+	today := time.Date(2024, time.March, 21, 12, 0, 0, 0, time.UTC)
+	s, err := j.Stats(today)
+	var diagnostics Diagnostics
+	if !errors.As(err, &diagnostics) || len(diagnostics) != 1 || diagnostics[0].File != missing.File {
+		t.Fatalf("expected a diagnostic for %s, actual %v", missing.File, err)
+	}
+
+	if s.Words != 12 {
+		t.Errorf("words = %d, expected 12", s.Words)
+	}
+	if s.Weekdays[time.Tuesday] != 2 || s.Weekdays[time.Wednesday] != 2 {
+		t.Errorf("weekdays = %v", s.Weekdays)
+	}
+	if s.LongestStreak.Days != 3 || s.LongestStreak.Start.Day() != 11 {
+		t.Errorf("longest streak = %+v, expected 3 days from 2024-03-11", s.LongestStreak)
+	}
+	if s.CurrentStreak.Days != 1 || s.CurrentStreak.Start.Day() != 20 {
+		t.Errorf("current streak = %+v, expected 1 day from 2024-03-20", s.CurrentStreak)
+	}
+
+	weeks, err := s.Periods(GroupByISOWeek)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(weeks) != 2 || weeks[0].Entries != 4 || weeks[0].Words != 9 || weeks[1].Words != 3 {
+		t.Errorf("weeks = %+v", weeks)
+	}
+
+	if s, _ = j.Stats(today.AddDate(0, 0, 2)); s.CurrentStreak.Days != 0 {
+		t.Errorf("current streak = %+v, expected none", s.CurrentStreak)
+	}
+
+	var b bytes.Buffer
+	from := time.Date(2024, time.March, 11, 0, 0, 0, 0, time.UTC)
+	s.WriteHeatmap(&b, from, from.AddDate(0, 0, 13))
+	expected := `
+    Mar
+Mon █·
+Tue █·
+Wed ░▓
+Thu ··
+Fri ··
+Sat ··
+Sun ··
+
+    Less · ░ ▒ ▓ █ More
+	`
+	if actual := strings.TrimSpace(b.String()); actual != strings.TrimSpace(expected) {
+		t.Errorf("heatmap: expected\n%s\nactual\n%s", expected, actual)
+	}
+
+	// Busier days outside the range do not make the range look pale.
+	b.Reset()
+	s.WriteHeatmap(&b, from.AddDate(0, 0, 7), from.AddDate(0, 0, 13))
+	if actual := b.String(); !strings.Contains(actual, "Wed █") {
+		t.Errorf("heatmap: expected the busiest day in range to be full\n%s", actual)
+	}
+}
+
+func TestHeatLevel(t *testing.T) {
+	testCases := []struct {
+		words, max, expected int
+	}{
+		{0, 10, 0},
+		{1, 1, 4},
+		{1, 100, 1},
+		{50, 100, 2},
+		{99, 100, 4},
+		{100, 100, 4},
+	}
+
+	for _, tc := range testCases {
+		if actual := heatLevel(tc.words, tc.max); actual != tc.expected {
+			t.Errorf("heatLevel(%d, %d) = %d, expected %d", tc.words, tc.max, actual, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package journal
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/taylorskalyo/markdown-journal/ctags"
+)
+
+// TagLines attaches the methods of sort.Interface to []ctags.TagLine.
+type TagLines []ctags.TagLine
+
+// Tags returns the entry's contents as ctags tags, in increasing order by line
+// number, headings first. The first heading is tagged as the entry's title.
+func (e Entry) Tags() (tags []ctags.TagLine) {
+	tag := func(name string, line int, fields ctags.TagFields) {
+		fields["line"] = strconv.Itoa(line)
+		tags = append(tags, ctags.TagLine{
+			TagName:    name,
+			TagFile:    e.File,
+			TagAddress: strconv.Itoa(line),
+			TagFields:  fields,
+		})
+	}
+
+	for i, h := range e.Headings {
+		kind := "heading"
+		if i == 0 {
+			kind = "title"
+		}
+		tag(h.Text, h.Line, ctags.TagFields{"kind": kind, "level": strconv.Itoa(h.Level)})
+	}
+
+	for _, o := range e.Occurrences {
+		fields := ctags.TagFields{"kind": "label"}
+		if o.Heading != "" {
+			fields["heading"] = o.Heading
+		}
+		if !o.Section.Empty() {
+			fields["section"] = fmt.Sprintf("%d-%d", o.Section.Start, o.Section.End)
+		}
+		tag(o.Name, o.Line, fields)
+	}
+
+	for _, t := range e.Tasks {
+		fields := ctags.TagFields{"kind": "task", "done": strconv.FormatBool(t.Done)}
+		if t.Heading != "" {
+			fields["heading"] = t.Heading
+		}
+		tag(t.Text, t.Line, fields)
+	}
+
+	for _, l := range e.Links {
+		fields := ctags.TagFields{"kind": "link", "style": string(l.Style), "target": l.Target}
+		if l.Heading != "" {
+			fields["heading"] = l.Heading
+		}
+		tag(l.Destination, l.Line, fields)
+	}
+
+	if e.Excerpt != "" {
+		line := e.excerptLine
+		if line < 1 {
+			line = 1
+		}
+		tag(entryName(e.File), line, ctags.TagFields{"kind": "excerpt", "excerpt": e.Excerpt})
+	}
+
+	sort.Stable(TagLines(tags))
+
+	return tags
+}
+
+// entriesFromTags builds entries from ctags tags, sorted by file. Tags of files
+// that are not valid journal entries are skipped and described by diagnostics.
+func entriesFromTags(tags TagLines) (entries []Entry, diagnostics Diagnostics) {
+	var skipped string
+
+	sort.Stable(tags)
+	for _, tag := range tags {
+		if tag.TagFile == skipped {
+			continue
+		}
+
+		if n := len(entries); n == 0 || entries[n-1].File != tag.TagFile {
+			e, err := NewEntry(tag.TagFile)
+			if err != nil {
+				message := fmt.Sprintf("not a valid journal entry: %v", err)
+				diagnostics = append(diagnostics, Diagnostic{File: tag.TagFile, Message: message})
+				skipped = tag.TagFile
+				continue
+			}
+			entries = append(entries, e)
+		}
+		e := &entries[len(entries)-1]
+
+		line := tag.Line()
+		heading := tag.TagFields["heading"]
+		switch tag.Kind() {
+		case "title", "heading":
+			level, _ := strconv.Atoi(tag.TagFields["level"])
+			e.Headings = append(e.Headings, Heading{Text: tag.TagName, Level: level, Line: line})
+		case "label":
+			o := LabelOccurrence{Name: tag.TagName, File: e.File, Line: line, Heading: heading}
+			fmt.Sscanf(tag.TagFields["section"], "%d-%d", &o.Section.Start, &o.Section.End)
+			e.Occurrences = append(e.Occurrences, o)
+		case "task":
+			done := tag.TagFields["done"] == "true"
+			e.Tasks = append(e.Tasks, Task{Text: tag.TagName, Done: done, File: e.File, Line: line, Heading: heading})
+		case "link":
+			e.Links = append(e.Links, Link{
+				Destination: tag.TagName,
+				Target:      tag.TagFields["target"],
+				Style:       LinkStyle(tag.TagFields["style"]),
+				File:        e.File,
+				Line:        line,
+				Heading:     heading,
+			})
+		case "excerpt":
+			e.Excerpt = tag.TagFields["excerpt"]
+			e.excerptLine = line
+		}
+	}
+
+	return entries, diagnostics
+}
+
+func (t TagLines) Len() int      { return len(t) }
+func (t TagLines) Swap(i, j int) { t[i], t[j] = t[j], t[i] }
+
+// Sort by tagfile then line number in increasing order. Headings appear first.
+func (t TagLines) Less(i, j int) bool {
+	a, b := t[i], t[j]
+
+	if a.TagFile != b.TagFile {
+		return a.TagFile < b.TagFile
+	}
+
+	if a.Line() != b.Line() {
+		return a.Line() < b.Line()
+	}
+
+	return isHeadingTag(a) && !isHeadingTag(b)
+}
+
+func isHeadingTag(tag ctags.TagLine) bool {
+	return tag.Kind() == "title" || tag.Kind() == "heading"
+}
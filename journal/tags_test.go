@@ -0,0 +1,49 @@
+package journal
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/taylorskalyo/markdown-journal/ctags"
+)
+
+func TestEntryTags(t *testing.T) {
+	e, err := NewEntry("diary/2006-01-02.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.Excerpt = "Busy day."
+	e.excerptLine = 3
+	e.Headings = []Heading{{Text: "Monday", Level: 1, Line: 1}, {Text: "Todo", Level: 2, Line: 5}}
+	e.Occurrences = []LabelOccurrence{
+		{Name: "work", File: e.File, Line: 3, Heading: "Monday", Section: LineRange{2, 4}},
+	}
+	e.Tasks = []Task{{Text: "Call Bob", Done: true, File: e.File, Line: 7, Heading: "Todo"}}
+	e.Links = []Link{{Destination: "2006-01-01", Target: "2006-01-01", Style: WikiLink, File: e.File, Line: 3, Heading: "Monday"}}
+
+	expected := `
+Monday	diary/2006-01-02.md	1;"	kind:title	level:1	line:1
+work	diary/2006-01-02.md	3;"	heading:Monday	kind:label	line:3	section:2-4
+2006-01-01	diary/2006-01-02.md	3;"	heading:Monday	kind:link	line:3	style:wiki	target:2006-01-01
+2006-01-02	diary/2006-01-02.md	3;"	excerpt:Busy day.	kind:excerpt	line:3
+Todo	diary/2006-01-02.md	5;"	kind:heading	level:2	line:5
+Call Bob	diary/2006-01-02.md	7;"	done:true	heading:Todo	kind:task	line:7
+`
+
+	var b bytes.Buffer
+	w := ctags.NewWriter(&b)
+	w.WriteAll(e.Tags())
+	if actual := b.String(); strings.TrimSpace(actual) != strings.TrimSpace(expected) {
+		t.Errorf("expected:\n%s\nactual:\n%s", expected, actual)
+	}
+
+	entries, diagnostics := entriesFromTags(ctags.NewReader(&b).ReadAll())
+	if len(diagnostics) > 0 {
+		t.Fatal(diagnostics)
+	}
+	if len(entries) != 1 || !reflect.DeepEqual(entries[0], e) {
+		t.Errorf("expected tags to round trip:\n%+v\nactual:\n%+v", e, entries)
+	}
+}
@@ -0,0 +1,105 @@
+package journal
+
+import (
+	"io"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// EntryGroup is a set of consecutive entries that fall within the same period
+// of time. Time is the start of that period.
+type EntryGroup struct {
+	Time    time.Time
+	Entries []Entry
+}
+
+// WriteTemplate executes a text/template against the journal and writes the
+// result to a writer. In addition to the standard template functions, the
+// template has access to the following:
+//
+//	date LAYOUT TIME       format a time using a Go layout string and locale
+//	relpath BASE TARGET    TARGET relative to the BASE directory
+//	heading N              heading delimiter N levels below the base level
+//	location ITEM          "file:line" location of a label, task, or link
+//	isoweek TIME           ISO 8601 week number
+//	groupByYear ENTRIES    group entries by year
+//	groupByMonth ENTRIES   group entries by month
+//	groupByWeek ENTRIES    group entries by ISO 8601 week
+func (j Journal) WriteTemplate(w io.Writer, text string, setters ...WriterOption) error {
+	opts := newWriterOptions(setters)
+
+	funcs := template.FuncMap{
+		"date":     func(layout string, t time.Time) string { return opts.Locale.Format(t, layout) },
+		"relpath":  relpath,
+		"heading":  func(n int) string { return strings.Repeat("#", opts.Level+n) },
+		"location": func(item interface{ Location() string }) string { return item.Location() },
+		"isoweek": func(t time.Time) int {
+			_, week := t.ISOWeek()
+			return week
+		},
+		"groupByYear":  groupBy(yearStart),
+		"groupByMonth": groupBy(monthStart),
+		"groupByWeek":  groupBy(isoWeekStart),
+	}
+
+	tmpl, err := template.New("journal").Funcs(funcs).Parse(text)
+	if err != nil {
+		return err
+	}
+
+	j.Entries = opts.entries(j.Entries)
+
+	return tmpl.Execute(w, j)
+}
+
+// groupBy returns a function that splits entries into groups of consecutive
+// entries sharing the same period, as determined by start.
+func groupBy(start func(time.Time) time.Time) func([]Entry) []EntryGroup {
+	return func(entries []Entry) (groups []EntryGroup) {
+		for _, entry := range entries {
+			t := start(entry.Time)
+			if n := len(groups); n == 0 || !groups[n-1].Time.Equal(t) {
+				groups = append(groups, EntryGroup{Time: t})
+			}
+			g := &groups[len(groups)-1]
+			g.Entries = append(g.Entries, entry)
+		}
+
+		return groups
+	}
+}
+
+func yearStart(t time.Time) time.Time {
+	return time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, t.Location())
+}
+
+func monthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+// isoWeekStart returns the Monday beginning the ISO 8601 week of t.
+func isoWeekStart(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, t.Location())
+}
+
+// relpath returns target relative to the base directory. If no relative path
+// can be determined, target is returned unchanged.
+func relpath(base, target string) string {
+	rel, err := filepath.Rel(base, target)
+	if err != nil {
+		// Rel requires both paths to be either absolute or relative.
+		absBase, baseErr := filepath.Abs(base)
+		absTarget, targetErr := filepath.Abs(target)
+		if baseErr != nil || targetErr != nil {
+			return target
+		}
+		if rel, err = filepath.Rel(absBase, absTarget); err != nil {
+			return target
+		}
+	}
+
+	return filepath.ToSlash(rel)
+}
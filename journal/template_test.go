@@ -0,0 +1,108 @@
+package journal
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/taylorskalyo/markdown-journal/ctags"
+)
+
+func TestWriteTemplate(t *testing.T) {
+	format := `
+============= case %s ================
+Template Input:
+-----------
+%v
+Expected Output:
+----------
+%v
+Actual Output:
+----------
+%v
+`
+
+	tags := `
+02 Monday	diary/2006-01-02.md	1;"	kind:title	line:1
+03 Tuesday	diary/2006-01-03.md	1;"	kind:title	line:1
+recipe	diary/2006-01-03.md	5;"	heading:03 Tuesday	kind:label	line:5
+05 Sunday	diary/2006-02-05.md	1;"	kind:title	line:1
+30 Friday	diary/2007-11-30.md	1;"	kind:title	line:1
+`
+
+	cases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			`org-mode timeline`,
+			`
+{{- range groupByYear .Entries}}
+* {{date "2006" .Time}}
+{{- range groupByMonth .Entries}}
+** {{date "January" .Time}}
+{{- range .Entries}}
+- [[file:{{relpath "diary" .File}}][{{.Title}}]]
+{{- end}}
+{{- end}}
+{{- end}}
+			`,
+			`
+* 2007
+** November
+- [[file:2007-11-30.md][30 Friday]]
+* 2006
+** February
+- [[file:2006-02-05.md][05 Sunday]]
+** January
+- [[file:2006-01-03.md][03 Tuesday]]
+- [[file:2006-01-02.md][02 Monday]]
+			`,
+		},
+		{
+			`labels with locations`,
+			`
+{{- range .Labels}}
+{{heading 0}} {{.Name}}
+{{- range .Occurrences}}
+{{location .}}
+{{- end}}
+{{- end}}
+			`,
+			`
+# recipe
+diary/2006-01-03.md:5
+			`,
+		},
+		{
+			`weekly groups and entry tags`,
+			`
+{{- range groupByWeek .Entries}}
+Week {{isoweek .Time}}: {{len .Entries}}
+{{- range .Entries}}{{range .Tags}} {{.TagName}}{{end}}{{end}}
+{{- end}}
+			`,
+			`
+Week 48: 1 30 Friday
+Week 5: 1 05 Sunday
+Week 1: 2 03 Tuesday recipe 02 Monday
+			`,
+		},
+	}
+
+	for _, tc := range cases {
+		var b bytes.Buffer
+
+		r := ctags.NewReader(strings.NewReader(tags))
+		j := NewJournal(r.ReadAll())
+		if err := j.WriteTemplate(&b, tc.input); err != nil {
+			t.Errorf("case %s: %v", tc.name, err)
+		}
+		actual := strings.TrimSpace(b.String())
+		expected := strings.TrimSpace(tc.expected)
+		if actual != expected {
+			t.Errorf(format, tc.name, tc.input, expected, actual)
+		}
+	}
+}
@@ -3,46 +3,220 @@ package journal
 import (
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
 	"strings"
 	"time"
 )
 
+// Grouping determines the headings under which the timeline groups entries.
+type Grouping string
+
+// Supported timeline groupings.
+const (
+	GroupByYear    Grouping = "year"
+	GroupByMonth   Grouping = "month"
+	GroupByISOWeek Grouping = "isoweek"
+	GroupByWeek    Grouping = "week"
+	GroupByDay     Grouping = "day"
+	GroupByNone    Grouping = "none"
+)
+
+// timelineHeading is a heading in the timeline. A new heading is written
+// whenever its key changes.
+type timelineHeading struct {
+	key  string
+	text string
+}
+
 // WriteTimeline generates a timeline view of entries and writes the result to
 // a writer.
 func (j Journal) WriteTimeline(w io.Writer, setters ...WriterOption) error {
-	var year int
-	var month time.Month
+	opts := newWriterOptions(setters)
 
-	opts := &WriterOptions{
-		Level: 1,
+	groups, err := opts.headings()
+	if err != nil {
+		return err
 	}
 
-	for _, setter := range setters {
-		setter(opts)
-	}
+	var prev []timelineHeading
 
 	baseHeadingDelim := strings.Repeat("#", opts.Level)
-	for _, entry := range j.Entries {
-		// Write new year when it changes
-		if year != entry.Time.Year() {
-			year = entry.Time.Year()
-			fmt.Fprintf(w, "\n%s %s\n", baseHeadingDelim, entry.Time.Format(yearFormat))
+	for _, entry := range opts.entries(j.Entries) {
+		// Write headings that changed since the previous entry. Once a heading
+		// changes, all headings nested beneath it are written too.
+		changed := false
+		headings := groups(entry.Time)
+		for i, h := range headings {
+			if !changed && i < len(prev) && prev[i].key == h.key {
+				continue
+			}
+			changed = true
+			fmt.Fprintf(w, "\n%s%s %s\n", baseHeadingDelim, strings.Repeat("#", i), h.text)
 		}
+		prev = headings
 
-		// Write new month when it changes
-		if month != entry.Time.Month() {
-			month = entry.Time.Month()
-			fmt.Fprintf(w, "\n%s# %s\n", baseHeadingDelim, entry.Time.Format(monthFormat))
+		// Write day, and link to the entry
+		title := entry.Title()
+		if opts.GroupBy == GroupByDay {
+			if title == "" {
+				title = entry.File
+			}
+			fmt.Fprintf(w, "* [%s](%s)\n", title, opts.link(entry.File))
+			opts.writeExcerpt(w, entry.Excerpt)
+			continue
 		}
 
-		// Write day, and link to the entry
-		fmt.Fprintf(w, "* [%s](%s)", entry.Time.Format(dayFormat), entry.File)
-		if title := entry.Title(); title != "" {
+		fmt.Fprintf(w, "* [%s](%s)", opts.Locale.Format(entry.Time, opts.DayLayout), opts.link(entry.File))
+		if title != "" {
 			fmt.Fprintf(w, " - %s\n", title)
 		} else {
 			fmt.Fprintf(w, "\n")
 		}
+		opts.writeExcerpt(w, entry.Excerpt)
 	}
 
 	return nil
 }
+
+// WriteTimelineSplit writes the timeline for each year to a separate file
+// named YYYY.md in dir, along with an index.md file linking to each of them.
+func (j Journal) WriteTimelineSplit(dir string, setters ...WriterOption) (err error) {
+	opts := newWriterOptions(setters)
+
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	index, err := os.Create(filepath.Join(dir, "index.md"))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := index.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	years := groupBy(yearStart)(opts.entries(j.Entries))
+	for _, year := range years {
+		file := filepath.Join(dir, year.Time.Format(yearFormat)+".md")
+		f, err := os.Create(file)
+		if err != nil {
+			return err
+		}
+
+		// Entries have already been paginated.
+		yearJournal := Journal{Entries: year.Entries}
+		err = yearJournal.WriteTimeline(f, append(setters, LinkDir(dir), Limit(0), Offset(0))...)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return err
+		}
+
+		noun := "entries"
+		if len(year.Entries) == 1 {
+			noun = "entry"
+		}
+		_, err = fmt.Fprintf(index, "* [%s](%s) - %d %s\n", opts.Locale.Format(year.Time, opts.YearLayout), relpath(dir, file), len(year.Entries), noun)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// headings returns a function that determines the timeline headings for a
+// given time, outermost first.
+func (opts WriterOptions) headings() (func(time.Time) []timelineHeading, error) {
+	year := func(t time.Time) timelineHeading {
+		return timelineHeading{t.Format("2006"), opts.Locale.Format(t, opts.YearLayout)}
+	}
+	month := func(t time.Time) timelineHeading {
+		return timelineHeading{t.Format("2006-01"), opts.Locale.Format(t, opts.MonthLayout)}
+	}
+	day := func(t time.Time) timelineHeading {
+		return timelineHeading{t.Format(dateFormat), opts.Locale.Format(t, opts.DayLayout)}
+	}
+
+	switch opts.GroupBy {
+	case GroupByYear:
+		return func(t time.Time) []timelineHeading {
+			return []timelineHeading{year(t)}
+		}, nil
+	case GroupByMonth, "":
+		return func(t time.Time) []timelineHeading {
+			return []timelineHeading{year(t), month(t)}
+		}, nil
+	case GroupByISOWeek:
+		return func(t time.Time) []timelineHeading {
+			start := isoWeekStart(t)
+			isoYear, week := t.ISOWeek()
+			// The ISO week-numbering year is the year of the week's Thursday.
+			y := year(start.AddDate(0, 0, 3))
+			y.key = fmt.Sprintf("%d", isoYear)
+			return []timelineHeading{y, opts.week(week, start)}
+		}, nil
+	case GroupByWeek:
+		return func(t time.Time) []timelineHeading {
+			start, week := weekOf(t, opts.WeekStart)
+			w := opts.week(week, start)
+			w.key = fmt.Sprintf("%d-%d", t.Year(), week)
+			return []timelineHeading{year(t), w}
+		}, nil
+	case GroupByDay:
+		return func(t time.Time) []timelineHeading {
+			return []timelineHeading{year(t), month(t), day(t)}
+		}, nil
+	case GroupByNone:
+		return func(t time.Time) []timelineHeading {
+			return nil
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown grouping: %s", opts.GroupBy)
+}
+
+// week returns a heading for a week starting on the given day (e.g. "Week 11
+// (Mar 11–17)").
+func (opts WriterOptions) week(week int, start time.Time) timelineHeading {
+	end := start.AddDate(0, 0, 6)
+	endLayout := "2"
+	if end.Month() != start.Month() {
+		endLayout = "Jan 2"
+	}
+	text := fmt.Sprintf("%s %d (%s–%s)", opts.Locale.Week, week,
+		opts.Locale.Format(start, "Jan 2"), opts.Locale.Format(end, endLayout))
+
+	return timelineHeading{start.Format(dateFormat), text}
+}
+
+// weekOf returns the first day of the week containing t and the week's number
+// within t's year. Weeks begin on the given weekday, and week 1 is the week
+// containing January 1.
+func weekOf(t time.Time, weekStart time.Weekday) (time.Time, int) {
+	offset := (int(t.Weekday()) - int(weekStart) + 7) % 7
+	start := time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, t.Location())
+
+	jan1 := time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, t.Location())
+	jan1Offset := (int(jan1.Weekday()) - int(weekStart) + 7) % 7
+	week := (t.YearDay()-1+jan1Offset)/7 + 1
+
+	return start, week
+}
+
+// ParseWeekday parses an English weekday name or its three-letter
+// abbreviation, ignoring case.
+func ParseWeekday(s string) (time.Weekday, error) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := d.String()
+		if strings.EqualFold(s, name) || strings.EqualFold(s, name[:3]) {
+			return d, nil
+		}
+	}
+
+	return time.Sunday, fmt.Errorf("unknown weekday: %s", s)
+}
@@ -2,8 +2,11 @@ package journal
 
 import (
 	"bytes"
+	"io/ioutil"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/taylorskalyo/markdown-journal/ctags"
 )
@@ -104,3 +107,225 @@ recipe	diary/2006-01-03-tantanmen-recipe.md	5;"	kind:label	line:5
 		}
 	}
 }
+
+func TestWriteTimelineGroupBy(t *testing.T) {
+	format := `
+============= case %s ================
+Expected Output:
+----------
+%v
+Actual Output:
+----------
+%v
+`
+
+	input := `
+Retro	diary/2024-03-17.md	1;"	kind:title	line:1
+Planning	diary/2024-03-11.md	1;"	kind:title	line:1
+Review	diary/2024-03-10.md	1;"	kind:title	line:1
+New Year	diary/2023-12-31.md	1;"	kind:title	line:1
+`
+
+	cases := []struct {
+		name     string
+		opts     []WriterOption
+		expected string
+	}{
+		{
+			`year`,
+			[]WriterOption{GroupBy(GroupByYear)},
+			`
+# 2024
+* [17 Sun](diary/2024-03-17.md) - Retro
+* [11 Mon](diary/2024-03-11.md) - Planning
+* [10 Sun](diary/2024-03-10.md) - Review
+
+# 2023
+* [31 Sun](diary/2023-12-31.md) - New Year
+			`,
+		},
+		{
+			`isoweek`,
+			[]WriterOption{GroupBy(GroupByISOWeek)},
+			`
+# 2024
+
+## Week 11 (Mar 11–17)
+* [17 Sun](diary/2024-03-17.md) - Retro
+* [11 Mon](diary/2024-03-11.md) - Planning
+
+## Week 10 (Mar 4–10)
+* [10 Sun](diary/2024-03-10.md) - Review
+
+# 2023
+
+## Week 52 (Dec 25–31)
+* [31 Sun](diary/2023-12-31.md) - New Year
+			`,
+		},
+		{
+			`week starting sunday`,
+			[]WriterOption{GroupBy(GroupByWeek), WeekStart(time.Sunday)},
+			`
+# 2024
+
+## Week 12 (Mar 17–23)
+* [17 Sun](diary/2024-03-17.md) - Retro
+
+## Week 11 (Mar 10–16)
+* [11 Mon](diary/2024-03-11.md) - Planning
+* [10 Sun](diary/2024-03-10.md) - Review
+
+# 2023
+
+## Week 53 (Dec 31–Jan 6)
+* [31 Sun](diary/2023-12-31.md) - New Year
+			`,
+		},
+		{
+			`day`,
+			[]WriterOption{GroupBy(GroupByDay)},
+			`
+# 2024
+
+## March
+
+### 17 Sun
+* [Retro](diary/2024-03-17.md)
+
+### 11 Mon
+* [Planning](diary/2024-03-11.md)
+
+### 10 Sun
+* [Review](diary/2024-03-10.md)
+
+# 2023
+
+## December
+
+### 31 Sun
+* [New Year](diary/2023-12-31.md)
+			`,
+		},
+		{
+			`none`,
+			[]WriterOption{GroupBy(GroupByNone)},
+			`
+* [17 Sun](diary/2024-03-17.md) - Retro
+* [11 Mon](diary/2024-03-11.md) - Planning
+* [10 Sun](diary/2024-03-10.md) - Review
+* [31 Sun](diary/2023-12-31.md) - New Year
+			`,
+		},
+	}
+
+	for _, tc := range cases {
+		var b bytes.Buffer
+
+		r := ctags.NewReader(strings.NewReader(input))
+		j := NewJournal(r.ReadAll())
+		j.WriteTimeline(&b, tc.opts...)
+		actual := strings.TrimSpace(b.String())
+		expected := strings.TrimSpace(tc.expected)
+		if actual != expected {
+			t.Errorf(format, tc.name, expected, actual)
+		}
+	}
+}
+
+func TestWriteTimelinePagination(t *testing.T) {
+	input := `
+a	diary/2024-03-17.md	1;"	kind:title	line:1
+b	diary/2024-03-11.md	1;"	kind:title	line:1
+c	diary/2023-12-31.md	1;"	kind:title	line:1
+`
+
+	cases := []struct {
+		name     string
+		opts     []WriterOption
+		expected string
+	}{
+		{`ascending`, []WriterOption{EntryOrder(Ascending)}, `c b a`},
+		{`limit`, []WriterOption{Limit(2)}, `a b`},
+		{`offset`, []WriterOption{Offset(1)}, `b c`},
+		{`offset past end`, []WriterOption{Offset(3)}, ``},
+		{`ascending page`, []WriterOption{EntryOrder(Ascending), Offset(1), Limit(1)}, `b`},
+	}
+
+	for _, tc := range cases {
+		var b bytes.Buffer
+
+		r := ctags.NewReader(strings.NewReader(input))
+		j := NewJournal(r.ReadAll())
+		j.WriteTimeline(&b, append(tc.opts, GroupBy(GroupByDay))...)
+
+		var titles []string
+		for _, line := range strings.Split(b.String(), "\n") {
+			if strings.HasPrefix(line, "* [") {
+				titles = append(titles, line[3:4])
+			}
+		}
+		if actual := strings.Join(titles, " "); actual != tc.expected {
+			t.Errorf("case %s: got %q, expected %q", tc.name, actual, tc.expected)
+		}
+	}
+}
+
+func TestWriteTimelineSplit(t *testing.T) {
+	input := `
+a	diary/2024-03-17.md	1;"	kind:title	line:1
+c	diary/2023-12-31.md	1;"	kind:title	line:1
+`
+
+	dir := filepath.Join(t.TempDir(), "timeline")
+	r := ctags.NewReader(strings.NewReader(input))
+	j := NewJournal(r.ReadAll())
+	if err := j.WriteTimelineSplit(dir, EntryOrder(Ascending)); err != nil {
+		t.Fatal(err)
+	}
+
+	index, err := ioutil.ReadFile(filepath.Join(dir, "index.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `
+* [2023](2023.md) - 1 entry
+* [2024](2024.md) - 1 entry
+	`
+	if actual := strings.TrimSpace(string(index)); actual != strings.TrimSpace(expected) {
+		t.Errorf("index: got\n%s\nexpected\n%s", actual, expected)
+	}
+
+	year, err := ioutil.ReadFile(filepath.Join(dir, "2024.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	link := relpath(dir, "diary/2024-03-17.md")
+	if !strings.HasPrefix(link, "../") || !strings.Contains(string(year), "("+link+")") {
+		t.Errorf("2024.md: expected link to %s, got\n%s", link, year)
+	}
+}
+
+func TestWriteTimelineExcerpt(t *testing.T) {
+	input := `
+Foo	diary/2006-01-03.md	1;"	kind:title	line:1
+2006-01-03	diary/2006-01-03.md	3;"	excerpt:A long\nfirst paragraph.	kind:excerpt	line:3
+`
+
+	expected := `
+# 2006
+
+## January
+* [03 Tue](diary/2006-01-03.md) - Foo
+  > A long first…
+`
+
+	var b bytes.Buffer
+
+	r := ctags.NewReader(strings.NewReader(input))
+	j := NewJournal(r.ReadAll())
+	j.WriteTimeline(&b, ExcerptLength(12))
+	if actual := strings.TrimSpace(b.String()); actual != strings.TrimSpace(expected) {
+		t.Errorf("expected:\n%s\nactual:\n%s", expected, actual)
+	}
+}
@@ -0,0 +1,195 @@
+package journal
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// VocabularyLabel is a canonical label declared in a vocabulary.
+type VocabularyLabel struct {
+	Name        string
+	Description string
+
+	// Aliases are accepted alternatives to the label's name. Deprecated names
+	// are also treated as the label, but should no longer be used.
+	Aliases    []string
+	Deprecated []string
+
+	// Any other properties declared for the label.
+	Metadata map[string]string
+}
+
+// Vocabulary is a set of canonical labels.
+type Vocabulary struct {
+	Labels []VocabularyLabel
+
+	// Canonical label names, keyed by the normalized name, alias, and
+	// deprecated name.
+	canonical  map[string]string
+	deprecated map[string]bool
+	norm       Normalization
+}
+
+// ReadVocabulary reads a vocabulary. Each label is declared by its name in
+// square brackets, followed by "key = value" properties:
+//
+//	# Comment
+//	[meeting]
+//	description = Team meetings and one-on-ones
+//	aliases = mtg, meet
+//	deprecated = meetings
+//
+// Aliases and deprecated names are comma-separated. Any other properties are
+// stored as metadata.
+func ReadVocabulary(r io.Reader) (v Vocabulary, err error) {
+	var label *VocabularyLabel
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			v.Labels = append(v.Labels, VocabularyLabel{
+				Name:     strings.TrimSpace(line[1 : len(line)-1]),
+				Metadata: map[string]string{},
+			})
+			label = &v.Labels[len(v.Labels)-1]
+			continue
+		}
+
+		p := strings.SplitN(line, "=", 2)
+		if len(p) != 2 || label == nil {
+			return v, fmt.Errorf("line %d: expected [label] or key = value", n)
+		}
+		key, value := strings.TrimSpace(p[0]), strings.TrimSpace(p[1])
+
+		switch key {
+		case "description":
+			label.Description = value
+		case "aliases":
+			label.Aliases = append(label.Aliases, splitList(value)...)
+		case "deprecated":
+			label.Deprecated = append(label.Deprecated, splitList(value)...)
+		default:
+			label.Metadata[key] = value
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return v, err
+	}
+
+	return v, v.index()
+}
+
+// Normalize returns a copy of the vocabulary that matches label names using
+// the given normalization. If normalization makes names of different labels
+// equal, the label declared first takes precedence.
+func (v Vocabulary) Normalize(n Normalization) Vocabulary {
+	v.norm = n
+	v.index()
+
+	return v
+}
+
+// index maps each normalized name, alias, and deprecated name to its label.
+// It returns an error if a name is declared by more than one label.
+func (v *Vocabulary) index() (err error) {
+	v.canonical = map[string]string{}
+	v.deprecated = map[string]bool{}
+
+	for _, l := range v.Labels {
+		names := append([]string{l.Name}, l.Aliases...)
+		for _, name := range append(names, l.Deprecated...) {
+			key := v.norm.Key(name)
+			if c, ok := v.canonical[key]; ok {
+				if c != l.Name && err == nil {
+					err = fmt.Errorf("%s is declared by both %s and %s", name, c, l.Name)
+				}
+				continue
+			}
+			v.canonical[key] = l.Name
+		}
+		for _, name := range l.Deprecated {
+			v.deprecated[v.norm.Key(name)] = true
+		}
+	}
+
+	return err
+}
+
+// ReadVocabularyFile reads a vocabulary from the named file.
+func ReadVocabularyFile(filename string) (Vocabulary, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return Vocabulary{}, err
+	}
+	defer f.Close()
+
+	v, err := ReadVocabulary(f)
+	if err != nil {
+		return v, fmt.Errorf("%s: %w", filename, err)
+	}
+
+	return v, nil
+}
+
+// Canonical returns the canonical name of a label. If the label is not part of
+// the vocabulary, its name is returned unchanged along with false.
+func (v Vocabulary) Canonical(name string) (string, bool) {
+	if c, ok := v.canonical[v.norm.Key(name)]; ok {
+		return c, true
+	}
+
+	return name, false
+}
+
+// IsDeprecated reports whether name is a deprecated name for a label.
+func (v Vocabulary) IsDeprecated(name string) bool {
+	return v.deprecated[v.norm.Key(name)]
+}
+
+// Lookup returns the vocabulary's declaration of a canonical label.
+func (v Vocabulary) Lookup(name string) (VocabularyLabel, bool) {
+	for _, l := range v.Labels {
+		if l.Name == name {
+			return l, true
+		}
+	}
+
+	return VocabularyLabel{}, false
+}
+
+// CheckVocabulary reports labels in the journal that are deprecated or not part
+// of the vocabulary. Problems are sorted by file and line.
+func (j Journal) CheckVocabulary(v Vocabulary) (problems []Problem) {
+	v = v.Normalize(j.options.Normalization)
+	for _, l := range j.Labels {
+		for _, o := range l.Occurrences {
+			c, ok := v.Canonical(o.Name)
+			switch {
+			case !ok:
+				problems = append(problems, Problem{o.File, o.Line, fmt.Sprintf("label not in vocabulary: %s", o.Name)})
+			case v.IsDeprecated(o.Name):
+				problems = append(problems, Problem{o.File, o.Line, fmt.Sprintf("deprecated label %s; use %s", o.Name, c)})
+			}
+		}
+	}
+	SortProblems(problems)
+
+	return problems
+}
+
+func splitList(s string) (items []string) {
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
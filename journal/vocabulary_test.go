@@ -0,0 +1,127 @@
+package journal
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/taylorskalyo/markdown-journal/ctags"
+)
+
+const testVocabulary = `
+# Labels used by the team
+[meeting]
+description = Team meetings and one-on-ones
+aliases = mtg, meet
+deprecated = meetings
+owner = ops
+
+[recipe]
+`
+
+func TestReadVocabulary(t *testing.T) {
+	v, err := ReadVocabulary(strings.NewReader(testVocabulary))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l, ok := v.Lookup("meeting")
+	if !ok {
+		t.Fatal("expected meeting to be declared")
+	}
+	if l.Description != "Team meetings and one-on-ones" {
+		t.Errorf("unexpected description: %q", l.Description)
+	}
+	if strings.Join(l.Aliases, ",") != "mtg,meet" {
+		t.Errorf("unexpected aliases: %v", l.Aliases)
+	}
+	if l.Metadata["owner"] != "ops" {
+		t.Errorf("unexpected metadata: %v", l.Metadata)
+	}
+
+	testCases := []struct {
+		name, canonical string
+		ok, deprecated  bool
+	}{
+		{"meeting", "meeting", true, false},
+		{"mtg", "meeting", true, false},
+		{"meetings", "meeting", true, true},
+		{"recipe", "recipe", true, false},
+		{"typo", "typo", false, false},
+	}
+
+	for _, tc := range testCases {
+		c, ok := v.Canonical(tc.name)
+		if c != tc.canonical || ok != tc.ok {
+			t.Errorf("%s: expected %s, %v; actual %s, %v", tc.name, tc.canonical, tc.ok, c, ok)
+		}
+		if v.IsDeprecated(tc.name) != tc.deprecated {
+			t.Errorf("%s: expected deprecated to be %v", tc.name, tc.deprecated)
+		}
+	}
+}
+
+func TestReadVocabularyErrors(t *testing.T) {
+	testCases := []string{
+		"description = no label",
+		"[a]\nnot a property",
+		"[a]\naliases = x\n[b]\ndeprecated = x",
+	}
+
+	for _, tc := range testCases {
+		if _, err := ReadVocabulary(strings.NewReader(tc)); err == nil {
+			t.Errorf("expected error for %q", tc)
+		}
+	}
+}
+
+func TestVocabularyLabels(t *testing.T) {
+	input := `
+mtg	diary/2006-01-02.md	2;"	kind:label	line:2
+meeting	diary/2006-01-03.md	2;"	kind:label	line:2
+meetings	diary/2006-01-04.md	2;"	kind:label	line:2
+typo	diary/2006-01-04.md	3;"	kind:label	line:3
+`
+
+	expected := `
+# meeting
+
+Team meetings and one-on-ones
+
+* [diary/2006-01-04.md:2](diary/2006-01-04.md:2)
+* [diary/2006-01-03.md:2](diary/2006-01-03.md:2)
+* [diary/2006-01-02.md:2](diary/2006-01-02.md:2)
+
+# typo
+* [diary/2006-01-04.md:3](diary/2006-01-04.md:3)
+`
+
+	v, err := ReadVocabulary(strings.NewReader(testVocabulary))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := ctags.NewReader(strings.NewReader(input))
+	j := NewJournal(r.ReadAll(), LabelVocabulary(v))
+
+	var b bytes.Buffer
+	if err := j.WriteLabels(&b); err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != expected {
+		t.Errorf("expected:\n%s\nactual:\n%s", expected, b.String())
+	}
+
+	expected = `
+diary/2006-01-04.md:2: deprecated label meetings; use meeting
+diary/2006-01-04.md:3: label not in vocabulary: typo
+`
+
+	var actual []string
+	for _, p := range j.CheckVocabulary(v) {
+		actual = append(actual, p.String())
+	}
+	if strings.Join(actual, "\n") != strings.TrimSpace(expected) {
+		t.Errorf("expected:\n%s\nactual:\n%s", strings.TrimSpace(expected), strings.Join(actual, "\n"))
+	}
+}
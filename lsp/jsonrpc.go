@@ -0,0 +1,144 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// JSON-RPC error codes.
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+)
+
+// message is a JSON-RPC request, notification, or response. Requests and
+// responses have an ID; notifications do not.
+type message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *responseError  `json:"error,omitempty"`
+}
+
+type responseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *responseError) Error() string {
+	return e.Message
+}
+
+// conn reads and writes JSON-RPC messages framed by a Content-Length header,
+// as used by the Language Server Protocol.
+type conn struct {
+	r *bufio.Reader
+
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func newConn(r io.Reader, w io.Writer) *conn {
+	return &conn{r: bufio.NewReader(r), w: w}
+}
+
+// read reads the next message. It returns io.EOF if the stream ends between
+// messages.
+func (c *conn) read() (m message, err error) {
+	length := -1
+	for {
+		line, err := c.r.ReadString('\n')
+		if err == io.EOF && line == "" && length < 0 {
+			return m, io.EOF
+		}
+		if err != nil {
+			return m, err
+		}
+
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+
+		p := strings.SplitN(line, ":", 2)
+		if len(p) == 2 && strings.EqualFold(strings.TrimSpace(p[0]), "Content-Length") {
+			if length, err = strconv.Atoi(strings.TrimSpace(p[1])); err != nil {
+				return m, fmt.Errorf("invalid Content-Length: %s", p[1])
+			}
+		}
+	}
+	if length < 0 {
+		return m, fmt.Errorf("missing Content-Length")
+	}
+
+	body := make([]byte, length)
+	if _, err = io.ReadFull(c.r, body); err != nil {
+		return m, err
+	}
+
+	if err = json.Unmarshal(body, &m); err != nil {
+		return m, &responseError{codeParseError, err.Error()}
+	}
+
+	return m, nil
+}
+
+// write writes a message.
+func (c *conn) write(m message) error {
+	m.JSONRPC = "2.0"
+	body, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, err = fmt.Fprintf(c.w, "Content-Length: %d\r\n\r\n", len(body)); err != nil {
+		return err
+	}
+	_, err = c.w.Write(body)
+
+	return err
+}
+
+// reply writes the response to a request. If err is not nil, it is sent as the
+// response's error instead of the result.
+func (c *conn) reply(id json.RawMessage, result interface{}, err error) error {
+	m := message{ID: id}
+	if err != nil {
+		rerr, ok := err.(*responseError)
+		if !ok {
+			rerr = &responseError{codeInternalError, err.Error()}
+		}
+		m.Error = rerr
+		return c.write(m)
+	}
+
+	// A successful response must have a result, even if it is null.
+	if m.Result, err = json.Marshal(result); err != nil {
+		return err
+	}
+
+	return c.write(m)
+}
+
+// notify writes a notification.
+func (c *conn) notify(method string, params interface{}) error {
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+
+	return c.write(message{Method: method, Params: raw})
+}
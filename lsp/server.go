@@ -0,0 +1,705 @@
+// Package lsp implements a Language Server for markdown journals.
+//
+// The server offers completion of existing labels after ":", go to definition
+// and references for labels, hover information for labels, document symbols
+// for headings, workspace symbols for entry titles, and diagnostics from the
+// journal's check rules.
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/taylorskalyo/markdown-journal/journal"
+)
+
+// Server is a Language Server for a journal. The journal is loaded from the
+// client's workspace root when the client initializes the server, and kept up
+// to date as documents are edited.
+type Server struct {
+	conn    *conn
+	parser  journal.FileParser
+	setters []journal.JournalOption
+	options journal.JournalOptions
+
+	journal journal.Journal
+
+	// Entries keyed by file, and the text of open documents keyed by file.
+	entries   map[string]journal.Entry
+	documents map[string]string
+
+	// Links to missing files in each open document. They are only looked up
+	// again when the document changes, as they require file system access.
+	missing map[string][]journal.Problem
+
+	// Open documents that have changed since the journal was last rebuilt.
+	changed map[string]bool
+
+	// Whether the client has requested a shutdown.
+	shutdown bool
+}
+
+// changeDelay is how long the server waits for further changes to documents
+// before rebuilding the journal, so that it is not rebuilt on every keystroke.
+const changeDelay = 200 * time.Millisecond
+
+// NewServer returns a new Server that reads requests from r and writes
+// responses to w. The journal is built using the given options.
+func NewServer(r io.Reader, w io.Writer, setters ...journal.JournalOption) *Server {
+	s := &Server{
+		conn:      newConn(r, w),
+		parser:    journal.NewFileParser(),
+		setters:   setters,
+		entries:   map[string]journal.Entry{},
+		documents: map[string]string{},
+		missing:   map[string][]journal.Problem{},
+		changed:   map[string]bool{},
+	}
+	for _, setter := range setters {
+		setter(&s.options)
+	}
+
+	return s
+}
+
+// Serve handles requests until the client sends an exit notification or
+// closes the connection, or the context is done. An exit notification that is
+// not preceded by a shutdown request is returned as an error.
+//
+// Changes to documents are applied once no further changes have been received
+// for a short while, or before the next request is handled.
+func (s *Server) Serve(ctx context.Context) error {
+	messages := make(chan message)
+	errs := make(chan error, 1)
+	go func() {
+		for {
+			m, err := s.conn.read()
+			var rerr *responseError
+			if errors.As(err, &rerr) {
+				s.conn.reply(json.RawMessage("null"), nil, rerr)
+				continue
+			}
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			select {
+			case messages <- m:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var changed <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-errs:
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		case <-changed:
+			changed = nil
+			if err := s.flush(); err != nil {
+				return err
+			}
+		case m := <-messages:
+			if m.Method != "textDocument/didChange" {
+				changed = nil
+				if err := s.flush(); err != nil {
+					return err
+				}
+			}
+
+			if m.Method == "exit" {
+				if !s.shutdown {
+					return errors.New("exit notification received without a shutdown request")
+				}
+				return nil
+			}
+			if s.shutdown {
+				// Only exit is handled after a shutdown request.
+				if m.ID != nil {
+					rerr := &responseError{codeInvalidRequest, fmt.Sprintf("%s received after shutdown", m.Method)}
+					if err := s.conn.reply(m.ID, nil, rerr); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+
+			result, err := s.handle(m)
+			if len(s.changed) > 0 {
+				changed = time.After(changeDelay)
+			}
+			if m.ID == nil {
+				if err != nil {
+					log.Printf("%s: %v", m.Method, err)
+				}
+				continue
+			}
+			if err = s.conn.reply(m.ID, result, err); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// handle handles a request or notification and returns the result.
+func (s *Server) handle(m message) (interface{}, error) {
+	params := func(v interface{}) error {
+		if err := json.Unmarshal(m.Params, v); err != nil {
+			return &responseError{codeInvalidParams, err.Error()}
+		}
+		return nil
+	}
+
+	switch m.Method {
+	case "initialize":
+		var p initializeParams
+		if err := params(&p); err != nil {
+			return nil, err
+		}
+		return s.initialize(p)
+	case "initialized", "$/cancelRequest", "$/setTrace":
+		return nil, nil
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+	case "textDocument/didOpen":
+		var p didOpenParams
+		if err := params(&p); err != nil {
+			return nil, err
+		}
+		return nil, s.didOpen(p)
+	case "textDocument/didChange":
+		var p didChangeParams
+		if err := params(&p); err != nil {
+			return nil, err
+		}
+		return nil, s.didChange(p)
+	case "textDocument/didSave":
+		var p didSaveParams
+		if err := params(&p); err != nil {
+			return nil, err
+		}
+		return nil, s.didSave(p)
+	case "textDocument/didClose":
+		var p didCloseParams
+		if err := params(&p); err != nil {
+			return nil, err
+		}
+		return nil, s.didClose(p)
+	case "textDocument/completion":
+		var p textDocumentPositionParams
+		if err := params(&p); err != nil {
+			return nil, err
+		}
+		return s.completion(p)
+	case "textDocument/definition":
+		var p textDocumentPositionParams
+		if err := params(&p); err != nil {
+			return nil, err
+		}
+		return s.definition(p)
+	case "textDocument/references":
+		var p textDocumentPositionParams
+		if err := params(&p); err != nil {
+			return nil, err
+		}
+		return s.references(p)
+	case "textDocument/hover":
+		var p textDocumentPositionParams
+		if err := params(&p); err != nil {
+			return nil, err
+		}
+		return s.hover(p)
+	case "textDocument/documentSymbol":
+		var p documentSymbolParams
+		if err := params(&p); err != nil {
+			return nil, err
+		}
+		return s.documentSymbols(p)
+	case "workspace/symbol":
+		var p workspaceSymbolParams
+		if err := params(&p); err != nil {
+			return nil, err
+		}
+		return s.workspaceSymbols(p), nil
+	}
+
+	if m.ID == nil {
+		// Unknown notifications are ignored.
+		return nil, nil
+	}
+
+	return nil, &responseError{codeMethodNotFound, fmt.Sprintf("method not found: %s", m.Method)}
+}
+
+// initialize loads the journal in the workspace root and returns the server's
+// capabilities.
+func (s *Server) initialize(p initializeParams) (initializeResult, error) {
+	root := p.RootPath
+	if p.RootURI != "" {
+		var err error
+		if root, err = uriToPath(p.RootURI); err != nil {
+			return initializeResult{}, err
+		}
+	}
+	if root == "" {
+		root = "."
+	}
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return initializeResult{}, err
+	}
+
+	files, err := journal.Files([]string{root}, true)
+	if err = warn(err); err != nil {
+		return initializeResult{}, err
+	}
+	entries, err := journal.ParseEntries(files, 0)
+	if err = warn(err); err != nil {
+		return initializeResult{}, err
+	}
+	for _, e := range entries {
+		s.entries[e.File] = e
+	}
+	s.rebuild()
+
+	return initializeResult{
+		Capabilities: serverCapabilities{
+			TextDocumentSync: textDocumentSyncOptions{
+				OpenClose: true,
+				Change:    syncFull,
+				Save:      saveOptions{IncludeText: true},
+			},
+			CompletionProvider:      completionOptions{TriggerCharacters: []string{":"}},
+			DefinitionProvider:      true,
+			ReferencesProvider:      true,
+			HoverProvider:           true,
+			DocumentSymbolProvider:  true,
+			WorkspaceSymbolProvider: true,
+		},
+		ServerInfo: serverInfo{Name: "markdown-journal"},
+	}, nil
+}
+
+// warn logs diagnostics as warnings. Other errors are returned unchanged.
+func warn(err error) error {
+	var diagnostics journal.Diagnostics
+	if !errors.As(err, &diagnostics) {
+		return err
+	}
+
+	for _, d := range diagnostics {
+		log.Printf("warning: %s", d)
+	}
+
+	return nil
+}
+
+func (s *Server) didOpen(p didOpenParams) error {
+	file, err := uriToPath(p.TextDocument.URI)
+	if err != nil {
+		return err
+	}
+
+	s.documents[file] = p.TextDocument.Text
+	s.update(file)
+
+	return s.publishDiagnostics()
+}
+
+func (s *Server) didChange(p didChangeParams) error {
+	file, err := uriToPath(p.TextDocument.URI)
+	if err != nil {
+		return err
+	}
+	if len(p.ContentChanges) == 0 {
+		return nil
+	}
+
+	// With full document sync, the last change is the document's text. The
+	// journal is rebuilt by flush.
+	s.documents[file] = p.ContentChanges[len(p.ContentChanges)-1].Text
+	s.changed[file] = true
+
+	return nil
+}
+
+func (s *Server) didSave(p didSaveParams) error {
+	file, err := uriToPath(p.TextDocument.URI)
+	if err != nil {
+		return err
+	}
+	if p.Text != nil {
+		s.documents[file] = *p.Text
+	}
+	s.update(file)
+
+	return s.publishDiagnostics()
+}
+
+func (s *Server) didClose(p didCloseParams) error {
+	file, err := uriToPath(p.TextDocument.URI)
+	if err != nil {
+		return err
+	}
+
+	delete(s.documents, file)
+	s.update(file)
+
+	// Clear the closed document's diagnostics.
+	err = s.conn.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{
+		URI:         p.TextDocument.URI,
+		Diagnostics: []Diagnostic{},
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.publishDiagnostics()
+}
+
+// flush rebuilds the journal with the documents that have changed since it
+// was last rebuilt, and publishes diagnostics.
+func (s *Server) flush() error {
+	if len(s.changed) == 0 {
+		return nil
+	}
+
+	files := make([]string, 0, len(s.changed))
+	for file := range s.changed {
+		files = append(files, file)
+	}
+	s.changed = map[string]bool{}
+	s.update(files...)
+
+	return s.publishDiagnostics()
+}
+
+// update parses files, using the text of open documents where there is one,
+// and rebuilds the journal. Files that are not journal entries are removed. The
+// links to missing files in open documents are looked up again.
+func (s *Server) update(files ...string) {
+	for _, file := range files {
+		var e journal.Entry
+		var err error
+		if text, ok := s.documents[file]; ok {
+			e, err = s.parser.ParseEntrySource(file, []byte(text))
+		} else {
+			e, err = s.parser.ParseEntry(file)
+		}
+
+		if err != nil {
+			delete(s.entries, file)
+		} else {
+			s.entries[file] = e
+		}
+	}
+	s.rebuild()
+
+	for _, file := range files {
+		if _, ok := s.documents[file]; ok {
+			s.missing[file] = s.journal.CheckFiles(file)
+		} else {
+			delete(s.missing, file)
+		}
+	}
+}
+
+// rebuild builds the journal from the entries.
+func (s *Server) rebuild() {
+	entries := make([]journal.Entry, 0, len(s.entries))
+	for _, e := range s.entries {
+		entries = append(entries, e)
+	}
+
+	s.journal = journal.FromEntries(entries, s.setters...)
+	for _, e := range s.journal.Entries {
+		s.entries[e.File] = e
+	}
+}
+
+// publishDiagnostics reports the journal's problems in each open document.
+// Links to missing files are the ones last found by update, so that the file
+// system is not searched for the links of every document on each change.
+func (s *Server) publishDiagnostics() error {
+	problems := s.journal.CheckEntries()
+	for _, missing := range s.missing {
+		problems = append(problems, missing...)
+	}
+	if len(s.options.Vocabulary.Labels) > 0 {
+		problems = append(problems, s.journal.CheckVocabulary(s.options.Vocabulary)...)
+	}
+	journal.SortProblems(problems)
+
+	diagnostics := map[string][]Diagnostic{}
+	src := s.sources()
+	for _, p := range problems {
+		if _, ok := s.documents[p.File]; !ok {
+			continue
+		}
+		diagnostics[p.File] = append(diagnostics[p.File], Diagnostic{
+			Range:    src.lineRange(p.File, p.Line),
+			Severity: severityWarning,
+			Source:   "markdown-journal",
+			Message:  p.Message,
+		})
+	}
+
+	var open []string
+	for file := range s.documents {
+		open = append(open, file)
+	}
+	sort.Strings(open)
+
+	for _, file := range open {
+		d := diagnostics[file]
+		if d == nil {
+			d = []Diagnostic{}
+		}
+		err := s.conn.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{
+			URI:         pathToURI(file),
+			Diagnostics: d,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// completion offers the journal's labels when the cursor follows a ":" that
+// may start a label.
+func (s *Server) completion(p textDocumentPositionParams) ([]CompletionItem, error) {
+	file, err := uriToPath(p.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	items := []CompletionItem{}
+	line := s.sources().line(file, p.Position.Line+1)
+	if !labelPrefix(line, byteOffset(line, p.Position.Character)) {
+		return items, nil
+	}
+
+	for _, l := range s.journal.Labels {
+		items = append(items, CompletionItem{
+			Label:      l.Name,
+			Kind:       completionKindKeyword,
+			Detail:     occurrences(l),
+			InsertText: l.Name + ":",
+		})
+	}
+
+	return items, nil
+}
+
+// definition returns the location of the first use of the label under the
+// cursor, i.e. its occurrence in the oldest entry.
+func (s *Server) definition(p textDocumentPositionParams) ([]Location, error) {
+	l, _, ok, err := s.labelAt(p)
+	if err != nil || !ok || len(l.Occurrences) == 0 {
+		return []Location{}, err
+	}
+
+	first := l.Occurrences[0]
+	for _, o := range l.Occurrences[1:] {
+		a, b := s.entries[o.File].Time, s.entries[first.File].Time
+		if a.Before(b) || a.Equal(b) && o.File == first.File && o.Line < first.Line {
+			first = o
+		}
+	}
+
+	r, _ := s.sources().labelRange(first)
+
+	return []Location{{URI: pathToURI(first.File), Range: r}}, nil
+}
+
+// references returns the locations of all occurrences of the label under the
+// cursor.
+func (s *Server) references(p textDocumentPositionParams) ([]Location, error) {
+	l, _, ok, err := s.labelAt(p)
+	if err != nil || !ok {
+		return []Location{}, err
+	}
+
+	src := s.sources()
+	locations := make([]Location, 0, len(l.Occurrences))
+	for _, o := range l.Occurrences {
+		r, _ := src.labelRange(o)
+		locations = append(locations, Location{URI: pathToURI(o.File), Range: r})
+	}
+
+	return locations, nil
+}
+
+// hover describes the label under the cursor.
+func (s *Server) hover(p textDocumentPositionParams) (*Hover, error) {
+	l, r, ok, err := s.labelAt(p)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	entries := map[string]bool{}
+	for _, o := range l.Occurrences {
+		entries[o.File] = true
+	}
+	noun := "entries"
+	if len(entries) == 1 {
+		noun = "entry"
+	}
+
+	text := fmt.Sprintf("**%s**\n\n", l.Name)
+	if l.Description != "" {
+		text += l.Description + "\n\n"
+	}
+	text += fmt.Sprintf("%s in %d %s", occurrences(l), len(entries), noun)
+
+	return &Hover{Contents: markupContent{Kind: "markdown", Value: text}, Range: r}, nil
+}
+
+// documentSymbols returns the headings of a document.
+func (s *Server) documentSymbols(p documentSymbolParams) ([]SymbolInformation, error) {
+	file, err := uriToPath(p.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	src := s.sources()
+	symbols := []SymbolInformation{}
+	for _, h := range s.entries[file].Headings {
+		symbols = append(symbols, SymbolInformation{
+			Name:     h.Text,
+			Kind:     symbolKindString,
+			Location: Location{URI: p.TextDocument.URI, Range: src.lineRange(file, h.Line)},
+		})
+	}
+
+	return symbols, nil
+}
+
+// workspaceSymbols returns the entries whose titles contain the query,
+// ignoring case.
+func (s *Server) workspaceSymbols(p workspaceSymbolParams) []SymbolInformation {
+	query := strings.ToLower(p.Query)
+
+	src := s.sources()
+	symbols := []SymbolInformation{}
+	for _, e := range s.journal.Entries {
+		title := e.Title()
+		if title == "" || !strings.Contains(strings.ToLower(title), query) {
+			continue
+		}
+
+		line := 1
+		if len(e.Headings) > 0 {
+			line = e.Headings[0].Line
+		}
+		symbols = append(symbols, SymbolInformation{
+			Name:          title,
+			Kind:          symbolKindFile,
+			Location:      Location{URI: pathToURI(e.File), Range: src.lineRange(e.File, line)},
+			ContainerName: e.Time.Format("2006-01-02"),
+		})
+	}
+
+	return symbols
+}
+
+// labelAt returns the label under the cursor and the range of the occurrence.
+func (s *Server) labelAt(p textDocumentPositionParams) (l journal.Label, r Range, ok bool, err error) {
+	file, err := uriToPath(p.TextDocument.URI)
+	if err != nil {
+		return l, r, false, err
+	}
+
+	src := s.sources()
+	var occurrence journal.LabelOccurrence
+	for _, o := range s.entries[file].Occurrences {
+		if o.Line != p.Position.Line+1 {
+			continue
+		}
+		for _, span := range labelSpans(o.Line, src.line(file, o.Line), o.Name) {
+			if span.Start.Character <= p.Position.Character && p.Position.Character <= span.End.Character {
+				occurrence, r, ok = o, span, true
+			}
+		}
+	}
+	if !ok {
+		return l, r, false, nil
+	}
+
+	for _, l := range s.journal.Labels {
+		if l.Name == occurrence.Label {
+			return l, r, true, nil
+		}
+	}
+
+	return l, r, false, nil
+}
+
+func occurrences(l journal.Label) string {
+	if len(l.Occurrences) == 1 {
+		return "1 occurrence"
+	}
+
+	return fmt.Sprintf("%d occurrences", len(l.Occurrences))
+}
+
+// pathToURI returns the file URI of an absolute path. Windows paths starting
+// with a drive letter are given a leading slash, e.g. C:\journal becomes
+// file:///C:/journal.
+func pathToURI(path string) string {
+	path = filepath.ToSlash(path)
+	if hasDriveLetter(path) {
+		path = "/" + path
+	}
+
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
+
+// uriToPath returns the path of a file URI.
+func uriToPath(uri string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme != "file" {
+		return "", fmt.Errorf("unsupported URI: %s", uri)
+	}
+
+	path := u.Path
+	if strings.HasPrefix(path, "/") && hasDriveLetter(path[1:]) {
+		path = path[1:]
+	}
+
+	return filepath.FromSlash(path), nil
+}
+
+// hasDriveLetter reports whether a slash-separated path starts with a Windows
+// drive letter, e.g. C:/journal.
+func hasDriveLetter(path string) bool {
+	if len(path) < 2 || path[1] != ':' || (len(path) > 2 && path[2] != '/') {
+		return false
+	}
+	c := path[0]
+
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
@@ -0,0 +1,256 @@
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// client sends requests to a server and collects its notifications.
+type client struct {
+	t             *testing.T
+	conn          *conn
+	messages      chan message
+	id            int
+	notifications []message
+}
+
+func newClient(t *testing.T, r io.Reader, w io.Writer) *client {
+	c := &client{t: t, conn: newConn(r, w), messages: make(chan message, 100)}
+	go func() {
+		defer close(c.messages)
+		for {
+			m, err := c.conn.read()
+			if err != nil {
+				return
+			}
+			c.messages <- m
+		}
+	}()
+
+	return c
+}
+
+// request sends a request and decodes its result into result.
+func (c *client) request(method string, params, result interface{}) {
+	c.t.Helper()
+
+	c.id++
+	raw, _ := json.Marshal(params)
+	id := json.RawMessage(strconv.Itoa(c.id))
+	if err := c.conn.write(message{ID: id, Method: method, Params: raw}); err != nil {
+		c.t.Fatal(err)
+	}
+
+	for m := range c.messages {
+		if m.ID == nil {
+			c.notifications = append(c.notifications, m)
+			continue
+		}
+		if m.Error != nil {
+			c.t.Fatalf("%s: %s", method, m.Error.Message)
+		}
+		if err := json.Unmarshal(m.Result, result); err != nil {
+			c.t.Fatalf("%s: %v", method, err)
+		}
+		return
+	}
+	c.t.Fatalf("%s: connection closed", method)
+}
+
+// notify sends a notification.
+func (c *client) notify(method string, params interface{}) {
+	c.t.Helper()
+
+	if err := c.conn.notify(method, params); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+// diagnostics returns the messages of the last diagnostics published for a
+// document.
+func (c *client) diagnostics(uri string) (messages []string) {
+	for _, n := range c.notifications {
+		var p publishDiagnosticsParams
+		json.Unmarshal(n.Params, &p)
+		if n.Method != "textDocument/publishDiagnostics" || p.URI != uri {
+			continue
+		}
+		messages = nil
+		for _, d := range p.Diagnostics {
+			messages = append(messages, strconv.Itoa(d.Range.Start.Line)+": "+d.Message)
+		}
+	}
+
+	return messages
+}
+
+func TestServer(t *testing.T) {
+	dir := t.TempDir()
+	monday := filepath.Join(dir, "2006-01-02.md")
+	tuesday := filepath.Join(dir, "2006-01-03.md")
+	files := map[string]string{
+		monday:  "# Monday\n\n:work: :meal-prep:\n",
+		tuesday: "# Tuesday :work:\n\nSee [[2006-01-09]] and [photo](photo.png).\n",
+	}
+	for file, text := range files {
+		if err := ioutil.WriteFile(file, []byte(text), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	clientReader, serverWriter := io.Pipe()
+	serverReader, clientWriter := io.Pipe()
+	server := NewServer(serverReader, serverWriter)
+	done := make(chan error, 1)
+	go func() {
+		done <- server.Serve(context.Background())
+		serverWriter.Close()
+	}()
+	c := newClient(t, clientReader, clientWriter)
+
+	var initialized initializeResult
+	c.request("initialize", initializeParams{RootURI: pathToURI(dir)}, &initialized)
+	if !initialized.Capabilities.HoverProvider {
+		t.Errorf("expected hover capability")
+	}
+	c.notify("initialized", struct{}{})
+
+	uri := pathToURI(tuesday)
+	text := files[tuesday] + "\n:wo"
+	c.notify("textDocument/didOpen", didOpenParams{textDocumentItem{URI: uri, Text: text}})
+
+	position := func(line, character int) textDocumentPositionParams {
+		return textDocumentPositionParams{textDocumentIdentifier{uri}, Position{line, character}}
+	}
+
+	var items []CompletionItem
+	c.request("textDocument/completion", position(4, 3), &items)
+	var names []string
+	for _, item := range items {
+		names = append(names, item.Label)
+	}
+	if actual := strings.Join(names, " "); actual != "meal-prep work" {
+		t.Errorf("completion: expected labels, actual %q", actual)
+	}
+
+	c.request("textDocument/completion", position(2, 3), &items)
+	if len(items) != 0 {
+		t.Errorf("completion: expected no labels outside of a label, actual %v", items)
+	}
+
+	var locations []Location
+	c.request("textDocument/references", position(0, 12), &locations)
+	if len(locations) != 2 || locations[0].URI != uri || locations[0].Range != (Range{Position{0, 10}, Position{0, 16}}) ||
+		locations[1].URI != pathToURI(monday) || locations[1].Range != (Range{Position{2, 0}, Position{2, 6}}) {
+		t.Errorf("references: unexpected locations %+v", locations)
+	}
+
+	c.request("textDocument/definition", position(0, 12), &locations)
+	if len(locations) != 1 || locations[0].URI != pathToURI(monday) || locations[0].Range != (Range{Position{2, 0}, Position{2, 6}}) {
+		t.Errorf("definition: expected the first occurrence, actual %+v", locations)
+	}
+
+	var hover Hover
+	c.request("textDocument/hover", position(0, 12), &hover)
+	if !strings.Contains(hover.Contents.Value, "2 occurrences in 2 entries") {
+		t.Errorf("hover: unexpected contents %q", hover.Contents.Value)
+	}
+
+	var symbols []SymbolInformation
+	c.request("textDocument/documentSymbol", documentSymbolParams{textDocumentIdentifier{uri}}, &symbols)
+	if len(symbols) != 1 || symbols[0].Name != "Tuesday work" {
+		t.Errorf("document symbols: unexpected symbols %+v", symbols)
+	}
+
+	c.request("workspace/symbol", workspaceSymbolParams{Query: "mon"}, &symbols)
+	if len(symbols) != 1 || symbols[0].Name != "Monday" || symbols[0].Location.URI != pathToURI(monday) {
+		t.Errorf("workspace symbols: unexpected symbols %+v", symbols)
+	}
+
+	expected := "2: wiki link to missing entry: 2006-01-09\n2: link to missing file: photo.png"
+	if actual := strings.Join(c.diagnostics(uri), "\n"); actual != expected {
+		t.Errorf("diagnostics: expected %q, actual %q", expected, actual)
+	}
+
+	// Diagnostics are updated as the document changes.
+	if err := ioutil.WriteFile(filepath.Join(dir, "photo.png"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	var changes didChangeParams
+	changes.TextDocument.URI = uri
+	changes.ContentChanges = append(changes.ContentChanges, struct {
+		Text string `json:"text"`
+	}{"# Tuesday :work:\n\nSee [[2006-01-02]] and [photo](photo.png).\n"})
+	c.notify("textDocument/didChange", changes)
+
+	// The journal is rebuilt once no further changes are received.
+	select {
+	case m := <-c.messages:
+		if m.Method != "textDocument/publishDiagnostics" {
+			t.Errorf("expected diagnostics after a change, actual %+v", m)
+		}
+		c.notifications = append(c.notifications, m)
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected diagnostics after a change")
+	}
+
+	var result interface{}
+	c.request("shutdown", nil, &result)
+	if actual := c.diagnostics(uri); len(actual) != 0 {
+		t.Errorf("diagnostics: expected none after change, actual %q", actual)
+	}
+
+	// Requests other than exit are rejected after shutdown.
+	if err := c.conn.write(message{ID: json.RawMessage("100"), Method: "workspace/symbol", Params: json.RawMessage("{}")}); err != nil {
+		t.Fatal(err)
+	}
+	if m := <-c.messages; m.Error == nil || m.Error.Code != codeInvalidRequest {
+		t.Errorf("expected an invalid request error after shutdown, actual %+v", m)
+	}
+
+	c.notify("exit", nil)
+	if err := <-done; err != nil {
+		t.Errorf("serve: %v", err)
+	}
+}
+
+func TestServerExitWithoutShutdown(t *testing.T) {
+	clientReader, serverWriter := io.Pipe()
+	serverReader, clientWriter := io.Pipe()
+	defer clientReader.Close()
+	done := make(chan error, 1)
+	go func() {
+		done <- NewServer(serverReader, serverWriter).Serve(context.Background())
+	}()
+
+	c := newClient(t, clientReader, clientWriter)
+	c.notify("exit", nil)
+	if err := <-done; err == nil {
+		t.Error("expected an error for exit without shutdown")
+	}
+}
+
+func TestURIs(t *testing.T) {
+	testCases := map[string]string{
+		"/home/me/journal/2006-01-02.md": "file:///home/me/journal/2006-01-02.md",
+		"/home/me/my journal":            "file:///home/me/my%20journal",
+		"C:/Users/me/journal":            "file:///C:/Users/me/journal",
+		"c:":                             "file:///c:",
+	}
+
+	for path, uri := range testCases {
+		if actual := pathToURI(filepath.FromSlash(path)); actual != uri {
+			t.Errorf("%s: expected %s, actual %s", path, uri, actual)
+		}
+		if actual, err := uriToPath(uri); err != nil || actual != filepath.FromSlash(path) {
+			t.Errorf("%s: expected %s, actual %s (%v)", uri, path, actual, err)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package lsp
+
+import (
+	"io/ioutil"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/taylorskalyo/markdown-journal/journal"
+	"github.com/taylorskalyo/markdown-journal/markdown/extension"
+)
+
+// sources returns the lines of files, preferring the text of open documents
+// over the file on disk. Files are read at most once.
+type sources struct {
+	documents map[string]string
+	lines     map[string][]string
+}
+
+func (s *Server) sources() sources {
+	return sources{documents: s.documents, lines: map[string][]string{}}
+}
+
+// line returns the text of a line, starting at 1. It returns an empty string if
+// the line does not exist.
+func (src sources) line(file string, n int) string {
+	lines, ok := src.lines[file]
+	if !ok {
+		text, open := src.documents[file]
+		if !open {
+			b, _ := ioutil.ReadFile(file)
+			text = string(b)
+		}
+		lines = strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+		src.lines[file] = lines
+	}
+
+	if n < 1 || n > len(lines) {
+		return ""
+	}
+
+	return lines[n-1]
+}
+
+// lineRange returns the range of a line, starting at 1.
+func (src sources) lineRange(file string, n int) Range {
+	if n < 1 {
+		n = 1
+	}
+
+	return Range{
+		Start: Position{Line: n - 1},
+		End:   Position{Line: n - 1, Character: utf16Len(src.line(file, n))},
+	}
+}
+
+// labelRange returns the range of a label occurrence. If the label can't be
+// found on its line, the range of the line is returned along with false.
+func (src sources) labelRange(o journal.LabelOccurrence) (Range, bool) {
+	if spans := labelSpans(o.Line, src.line(o.File, o.Line), o.Name); len(spans) > 0 {
+		return spans[0], true
+	}
+
+	return src.lineRange(o.File, o.Line), false
+}
+
+// labelSpans returns the ranges of each ":name:" in a line, starting at 1.
+func labelSpans(n int, line, name string) (spans []Range) {
+	label := ":" + name + ":"
+	for offset := 0; ; {
+		i := strings.Index(line[offset:], label)
+		if i < 0 {
+			return spans
+		}
+		start := offset + i
+		offset = start + len(label)
+		spans = append(spans, Range{
+			Start: Position{Line: n - 1, Character: utf16Len(line[:start])},
+			End:   Position{Line: n - 1, Character: utf16Len(line[:offset])},
+		})
+	}
+}
+
+// labelPrefix reports whether the text before offset is the start of a label,
+// i.e. a ":" followed by zero or more label characters.
+func labelPrefix(line string, offset int) bool {
+	prefix := line[:offset]
+	for {
+		r, size := utf8.DecodeLastRuneInString(prefix)
+		if size == 0 || !extension.IsLabel(string(r)) {
+			break
+		}
+		prefix = prefix[:len(prefix)-size]
+	}
+
+	if !strings.HasSuffix(prefix, ":") {
+		return false
+	}
+	prefix = strings.TrimSuffix(prefix, ":")
+
+	// The ":" must not be part of a word, URL, or "::" separator.
+	r, size := utf8.DecodeLastRuneInString(prefix)
+	return size == 0 || r != ':' && r != '/' && !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+// utf16Len returns the length of s in UTF-16 code units, which the protocol
+// uses for character offsets.
+func utf16Len(s string) (n int) {
+	for _, r := range s {
+		n += len(utf16.Encode([]rune{r}))
+	}
+
+	return n
+}
+
+// byteOffset converts a UTF-16 character offset in a line to a byte offset.
+func byteOffset(line string, character int) int {
+	n := 0
+	for i, r := range line {
+		if n >= character {
+			return i
+		}
+		n += len(utf16.Encode([]rune{r}))
+	}
+
+	return len(line)
+}
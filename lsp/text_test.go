@@ -0,0 +1,38 @@
+package lsp
+
+import "testing"
+
+func TestLabelPrefix(t *testing.T) {
+	cases := []struct {
+		line     string
+		expected bool
+	}{
+		{":", true},
+		{"text :wo", true},
+		{"(:café", true},
+		{"text:wo", false},
+		{"https://", false},
+		{"Module::", false},
+		{"text", false},
+	}
+
+	for _, tc := range cases {
+		if actual := labelPrefix(tc.line, len(tc.line)); actual != tc.expected {
+			t.Errorf("%q: expected %v, actual %v", tc.line, tc.expected, actual)
+		}
+	}
+}
+
+func TestUTF16Offsets(t *testing.T) {
+	line := "\U0001F600 café :x:"
+
+	if n := utf16Len(line); n != 11 {
+		t.Errorf("expected length 11, actual %d", n)
+	}
+	if i := byteOffset(line, 8); line[i:] != ":x:" {
+		t.Errorf("expected offset of label, actual %q", line[i:])
+	}
+	if spans := labelSpans(1, line, "x"); len(spans) != 1 || spans[0].Start.Character != 8 || spans[0].End.Character != 11 {
+		t.Errorf("unexpected spans %+v", spans)
+	}
+}
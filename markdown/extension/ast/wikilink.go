@@ -0,0 +1,39 @@
+package ast
+
+import (
+	gast "github.com/yuin/goldmark/ast"
+)
+
+// A WikiLink struct represents a wiki-style link, e.g. [[target|label]].
+type WikiLink struct {
+	gast.BaseInline
+	Destination []byte
+	Value       *gast.Text
+	Heading     *gast.Heading
+}
+
+// Dump implements Node.Dump.
+func (n *WikiLink) Dump(source []byte, level int) {
+	segment := n.Value.Segment
+	m := map[string]string{
+		"Destination": string(n.Destination),
+		"Value":       string(segment.Value(source)),
+	}
+	gast.DumpHelper(n, source, level, m, nil)
+}
+
+// KindWikiLink is a NodeKind of the WikiLink node.
+var KindWikiLink = gast.NewNodeKind("WikiLink")
+
+// Kind implements Node.Kind.
+func (n *WikiLink) Kind() gast.NodeKind {
+	return KindWikiLink
+}
+
+// NewWikiLink returns a new WikiLink node.
+func NewWikiLink(destination []byte, value *gast.Text) *WikiLink {
+	return &WikiLink{
+		Destination: destination,
+		Value:       value,
+	}
+}
@@ -38,7 +38,21 @@ func (s *labelParser) Trigger() []byte {
 }
 
 func isLabelRune(r rune) bool {
-	return r == '-' || r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+	// Combining marks are allowed so that decomposed accented letters (e.g. "e"
+	// followed by U+0301) are part of the label.
+	return r == '-' || r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r)
+}
+
+// IsLabel reports whether name is a valid label name, i.e. a non-empty
+// combination of letters, digits, underscores, and dashes.
+func IsLabel(name string) bool {
+	for _, r := range name {
+		if !isLabelRune(r) {
+			return false
+		}
+	}
+
+	return name != ""
 }
 
 func isBoundaryRune(r rune) bool {
@@ -76,7 +90,7 @@ func (s *labelParser) Parse(parent gast.Node, block text.Reader, pc parser.Conte
 	node := ast.NewLabel(value)
 	gast.MergeOrAppendTextSegment(node, labelSegment)
 
-	node.Heading = heading(parent)
+	node.Heading = Heading(parent)
 
 	block.Advance(stop + 1)
 	return node
@@ -123,9 +137,9 @@ func (e *label) Extend(m goldmark.Markdown) {
 	))
 }
 
-// heading finds the heading under which a node is nested. If the node is not
+// Heading finds the heading under which a node is nested. If the node is not
 // nested under a heading, nil is returned.
-func heading(node gast.Node) *gast.Heading {
+func Heading(node gast.Node) *gast.Heading {
 	for n := node; n != nil; n = n.Parent() {
 		for s := n; s != nil; s = s.PreviousSibling() {
 			if h, ok := s.(*gast.Heading); ok {
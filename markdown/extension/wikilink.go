@@ -0,0 +1,132 @@
+package extension
+
+import (
+	"bytes"
+
+	"github.com/taylorskalyo/markdown-journal/markdown/extension/ast"
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+/* Valid wiki links:
+ *   [[2024-03-15]] links to an entry by name
+ *   [[2024-03-15-retro|retro]] links to an entry, displaying "retro"
+ *
+ * Not wiki links:
+ *   [[]] has no target
+ *   [[foo
+ *   bar]] spans multiple lines
+ */
+
+type wikiLinkParser struct {
+}
+
+var defaultWikiLinkParser = &wikiLinkParser{}
+
+// NewWikiLinkParser return a new InlineParser that parses wiki link
+// expressions. It must be given a higher priority than the standard link
+// parser.
+func NewWikiLinkParser() parser.InlineParser {
+	return defaultWikiLinkParser
+}
+
+func (s *wikiLinkParser) Trigger() []byte {
+	return []byte{'['}
+}
+
+func (s *wikiLinkParser) Parse(parent gast.Node, block text.Reader, pc parser.Context) gast.Node {
+	line, segment := block.PeekLine()
+	if !bytes.HasPrefix(line, []byte("[[")) {
+		return nil
+	}
+
+	stop := bytes.Index(line, []byte("]]"))
+	if stop < 0 {
+		return nil
+	}
+
+	content := line[2:stop]
+	if bytes.ContainsAny(content, "[]\n") {
+		return nil
+	}
+
+	target := content
+	valueSegment := text.NewSegment(segment.Start+2, segment.Start+stop)
+	if i := bytes.IndexByte(content, '|'); i >= 0 {
+		target = content[:i]
+		valueSegment = text.NewSegment(segment.Start+2+i+1, segment.Start+stop)
+	}
+	target = bytes.TrimSpace(target)
+	if len(target) == 0 {
+		return nil
+	}
+
+	value := gast.NewTextSegment(valueSegment)
+	node := ast.NewWikiLink(append([]byte{}, target...), value)
+	gast.MergeOrAppendTextSegment(node, valueSegment)
+
+	node.Heading = Heading(parent)
+
+	block.Advance(stop + 2)
+	return node
+}
+
+func (s *wikiLinkParser) CloseBlock(parent gast.Node, pc parser.Context) {
+	// nothing to do
+}
+
+// WikiLinkHTMLRenderer is a renderer.NodeRenderer implementation that
+// renders WikiLink nodes.
+type WikiLinkHTMLRenderer struct {
+	html.Config
+}
+
+// NewWikiLinkHTMLRenderer returns a new WikiLinkHTMLRenderer.
+func NewWikiLinkHTMLRenderer(opts ...html.Option) renderer.NodeRenderer {
+	r := &WikiLinkHTMLRenderer{
+		Config: html.NewConfig(),
+	}
+	for _, opt := range opts {
+		opt.SetHTMLOption(&r.Config)
+	}
+	return r
+}
+
+// RegisterFuncs implements renderer.NodeRenderer.RegisterFuncs.
+func (r *WikiLinkHTMLRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindWikiLink, r.renderWikiLink)
+}
+
+func (r *WikiLinkHTMLRenderer) renderWikiLink(w util.BufWriter, source []byte, node gast.Node, entering bool) (gast.WalkStatus, error) {
+	n := node.(*ast.WikiLink)
+	if entering {
+		_, _ = w.WriteString(`<a href="`)
+		destination := append(append([]byte{}, n.Destination...), ".md"...)
+		_, _ = w.Write(util.EscapeHTML(util.URLEscape(destination, true)))
+		_, _ = w.WriteString(`">`)
+	} else {
+		_, _ = w.WriteString("</a>")
+	}
+	return gast.WalkContinue, nil
+}
+
+type wikiLink struct {
+}
+
+// WikiLink is an extension that allow you to use wiki link expressions like
+// '[[target|text]]' .
+var WikiLink = &wikiLink{}
+
+func (e *wikiLink) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithInlineParsers(
+		util.Prioritized(NewWikiLinkParser(), 199),
+	))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(NewWikiLinkHTMLRenderer(), 0),
+	))
+}